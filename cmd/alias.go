@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// resolveContextArg returns the context a command-line name refers to
+// Context names take precedence over aliases, so an alias never hides a context.
+func resolveContextArg(kubeConfig *api.Config, name string) string {
+	if _, exists := kubeConfig.Contexts[name]; exists {
+		return name
+	}
+	if contextName, ok := settings.ResolveAlias(name); ok {
+		ui.Debugf("alias '%s' stands for context '%s'", name, contextName)
+		return contextName
+	}
+	return name
+}
+
+// aliasCmd represents the alias command
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage short aliases for context names",
+	Long: `Manage short aliases for long context names such as EKS ARNs.
+
+An alias can be used anywhere a context name is accepted, for example
+'kontext switch p' or 'kontext delete p'. Aliases are stored in kontext's
+configuration file; a context with the same name always wins over an alias.
+
+Examples:
+  kontext alias add p arn:aws:eks:eu-west-1:123456789012:cluster/prod
+  kontext alias list
+  kontext alias rm p`,
+}
+
+// aliasAddCmd represents the alias add command
+var aliasAddCmd = &cobra.Command{
+	Use:               "add <alias> <context>",
+	Short:             "Add or change an alias",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: aliasAddCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias, contextName := args[0], args[1]
+
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+		contextName = resolveContextArg(kubeConfig, contextName)
+		if _, exists := kubeConfig.Contexts[contextName]; !exists {
+			return newCommandError("Error adding alias", fmt.Errorf("%w: '%s'", kubeconfig.ErrContextNotFound, contextName))
+		}
+		if _, exists := kubeConfig.Contexts[alias]; exists {
+			return newCommandError("Error adding alias",
+				fmt.Errorf("%w: a context named '%s' exists and would hide the alias", config.ErrInvalidConfig, alias))
+		}
+
+		previous, replaced := settings.ResolveAlias(alias)
+		err = saveConfig("aliases", func(file *config.Config) error {
+			if err := file.SetAlias(alias, contextName); err != nil {
+				return newCommandError("Error adding alias", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if replaced && previous != contextName {
+			ui.PrintSuccess(fmt.Sprintf("Alias '%s' now stands for", alias), contextName, fmt.Sprintf("(was %s)", previous))
+		} else {
+			ui.PrintSuccess(fmt.Sprintf("Alias '%s' stands for", alias), contextName)
+		}
+		return nil
+	},
+}
+
+// aliasRmCmd represents the alias rm command
+var aliasRmCmd = &cobra.Command{
+	Use:               "rm <alias>...",
+	Aliases:           []string{"remove"},
+	Short:             "Remove aliases",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: aliasCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := saveConfig("aliases", func(file *config.Config) error {
+			for _, alias := range args {
+				if err := file.RemoveAlias(alias); err != nil {
+					return newCommandError("Error removing alias", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range args {
+			ui.PrintSuccess("Removed alias", alias)
+		}
+		return nil
+	},
+}
+
+// aliasListCmd represents the alias list command
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List aliases",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := settings.AliasNames()
+		if len(names) == 0 {
+			ui.PrintNote("No aliases defined", "(add one with 'kontext alias add <alias> <context>')")
+			return nil
+		}
+
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+
+		items := make([]string, 0, len(names))
+		for _, alias := range names {
+			contextName, _ := settings.ResolveAlias(alias)
+			item := fmt.Sprintf("%-12s %s", alias, contextName)
+			if _, exists := kubeConfig.Contexts[contextName]; !exists {
+				item += " (missing)"
+			}
+			items = append(items, item)
+		}
+		ui.PrintList("Aliases:", items)
+		return nil
+	},
+}
+
+// aliasCompletion completes alias names with the context they stand for
+func aliasCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var suggestions []string
+	for _, alias := range settings.AliasNames() {
+		contextName, _ := settings.ResolveAlias(alias)
+		suggestions = append(suggestions, alias+"\talias for "+contextName)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// aliasAddCompletion completes the context of a new alias
+func aliasAddCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return contextCompletion(cmd, nil, toComplete)
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasAddCmd, aliasRmCmd, aliasListCmd)
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/audit"
+	"github.com/user-cube/kontext/pkg/hooks"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// auditLog returns the audit log with the configured path and rotation
+func auditLog() audit.Log {
+	path := settings.Audit.Path
+	if path == "" {
+		path = audit.DefaultPath()
+	}
+	return audit.Log{
+		Path:     path,
+		MaxSize:  int64(settings.Audit.MaxSize) << 20,
+		MaxFiles: settings.Audit.MaxFiles,
+	}
+}
+
+// newAuditEntry returns an audit entry for an operation on the loaded kubeconfig,
+// at the manager's time. In fragment mode the fragment glob stands for the kubeconfig.
+func newAuditEntry(m *kubeconfig.Manager, operation string) audit.Entry {
+	path := kubeconfig.GetKubeConfigPath()
+	if kubeconfig.IsFragmentMode() {
+		path = kubeconfig.GetFragmentGlob()
+	}
+	entry := audit.NewEntry(operation, path)
+	entry.Time = m.Now()
+	return entry
+}
+
+// recordAudit appends a kubeconfig change to the audit log
+// Failing to record it never interrupts the command, the change is already made.
+func recordAudit(entry audit.Entry) {
+	if !settings.Audit.Enabled {
+		return
+	}
+	if err := auditLog().Append(entry); err != nil {
+		ui.PrintWarning("Could not record the audit log", err.Error())
+	}
+}
+
+// auditChange records a context or namespace change in the audit log
+// The reason is set for changes kontext makes on its own.
+func auditChange(m *kubeconfig.Manager, change hooks.Change, reason string) {
+	operation := audit.OpSwitch
+	if change.Kind == "namespace" {
+		operation = audit.OpNamespace
+	}
+	entry := newAuditEntry(m, operation)
+	entry.Reason = reason
+	entry.OldContext, entry.OldNamespace = change.OldContext, change.OldNamespace
+	entry.NewContext, entry.NewNamespace = change.NewContext, change.NewNamespace
+	recordAudit(entry)
+}
+
+// auditRemoval records the removal of kubeconfig entries in the audit log
+func auditRemoval(m *kubeconfig.Manager, operation string, contexts, clusters, users []string) {
+	entry := newAuditEntry(m, operation)
+	entry.Contexts, entry.Clusters, entry.Users = contexts, clusters, users
+	recordAudit(entry)
+}
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of kubeconfig changes",
+	Long: `Show who changed the kubeconfig with kontext, from where and when.
+
+kontext appends every context switch, namespace change, deletion, prune and
+lint repair to an audit log with the time, user, host, terminal, kubeconfig and the old
+and new values. The log is JSONL, in kontext's state directory by default
+(audit.path in the config), and rotated by size (audit.maxSize and
+audit.maxFiles). Set audit.enabled to false to stop recording.
+
+--since and --until take a duration ago (30m, 12h, 7d), a date (2025-01-31)
+or a time (2025-01-31T15:04:05Z).
+
+Examples:
+  # Everything from the last day
+  kontext audit --since 24h
+
+  # Switches into production contexts last week
+  kontext audit --operation switch --context 'prod-*' --since 7d
+
+  # Export for a compliance report
+  kontext audit --since 2025-01-01 --until 2025-02-01 -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		filter, err := getAuditFilter(cmd, newManager().Now())
+		if err != nil {
+			return err
+		}
+
+		entries, err := auditLog().Load()
+		if err != nil {
+			return newCommandError("Error reading the audit log", err)
+		}
+		entries = filter.Apply(entries)
+
+		if !format.IsText() {
+			return printObject(format, output.AuditLog{Entries: entries})
+		}
+
+		if len(entries) == 0 {
+			ui.PrintNote("No audit entries found", fmt.Sprintf("(log: %s)", auditLog().Path))
+			return nil
+		}
+
+		items := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			items = append(items, fmt.Sprintf("%s  %-9s %-12s %s",
+				entry.Time.Local().Format(time.DateTime), entry.Operation, entry.User, entry.Summary()))
+		}
+		ui.PrintList("Audit log:", items)
+		return nil
+	},
+}
+
+// getAuditFilter builds the audit filter from the command's flags, with
+// durations counted back from now. Invalid values are usage errors.
+func getAuditFilter(cmd *cobra.Command, now time.Time) (audit.Filter, error) {
+	var filter audit.Filter
+
+	for _, flag := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		value, _ := cmd.Flags().GetString(flag.name)
+		if value == "" {
+			continue
+		}
+		t, err := parseTimeFlag(value, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --%s: %w", flag.name, err)
+		}
+		*flag.dst = t
+	}
+
+	filter.Operation, _ = cmd.Flags().GetString("operation")
+	if filter.Operation != "" && !slices.Contains(audit.Operations, filter.Operation) {
+		return filter, fmt.Errorf("invalid --operation '%s' (use %s)", filter.Operation, strings.Join(audit.Operations, ", "))
+	}
+
+	filter.Context, _ = cmd.Flags().GetString("context")
+	if filter.Context != "" {
+		if kubeconfig.IsPattern(filter.Context) {
+			if _, err := kubeconfig.MatchPattern("", filter.Context); err != nil {
+				return filter, err
+			}
+		} else if contextName, ok := settings.ResolveAlias(filter.Context); ok {
+			filter.Context = contextName
+		}
+	}
+	return filter, nil
+}
+
+// parseTimeFlag parses a --since or --until value relative to now
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("'%s' is not a duration such as 12h or 7d, a date or an RFC 3339 time", value)
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	// Add flags
+	auditCmd.Flags().String("since", "", "Only show changes after this time or duration ago")
+	auditCmd.Flags().String("until", "", "Only show changes before this time or duration ago")
+	auditCmd.Flags().String("operation", "", "Only show one operation: "+strings.Join(audit.Operations, ", "))
+	auditCmd.Flags().StringP("context", "c", "", "Only show changes involving a context name, alias or pattern")
+	addOutputFlag(auditCmd)
+}
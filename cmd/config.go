@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+	"sigs.k8s.io/yaml"
+)
+
+// settings is the effective user configuration of the running command
+// It holds the defaults until persistentPreRunE loads the configuration file.
+var settings = config.Default()
+
+// loadSettings loads the user configuration and applies its UI settings
+// A broken file doesn't prevent `kontext config edit` from fixing it.
+func loadSettings(cmd *cobra.Command) error {
+	loaded, err := config.Load()
+	if err != nil {
+		if cmd == configEditCmd {
+			ui.PrintWarning(fmt.Sprintf("Ignoring the configuration file: %v", err))
+			return nil
+		}
+		return newCommandError("Error loading configuration", err)
+	}
+	settings = loaded
+
+	ui.SetSelectorSize(settings.Selector.Size)
+	ui.SetContextAliases(settings.Aliases)
+	ui.SetProtectedContexts(settings.IsProtected)
+	kubeconfig.SetFragments(settings.Kubeconfig.Glob, settings.Kubeconfig.Overlay)
+	ui.Debugf("loaded configuration from %s", config.GetConfigPath())
+	return nil
+}
+
+// saveConfig applies change to the configuration file and saves it
+// Only the file's own values are saved, never environment overrides; the
+// settings of the running command are reloaded afterwards. what names the
+// changed settings in errors, for example "aliases".
+func saveConfig(what string, change func(file *config.Config) error) error {
+	path := config.GetConfigPath()
+	file, err := config.LoadFile(path)
+	if err != nil {
+		return newCommandError("Error loading configuration", err)
+	}
+	if err := change(file); err != nil {
+		return err
+	}
+	if err := file.Validate(); err != nil {
+		return newCommandError("Error saving "+what, err)
+	}
+	if err := file.Save(path); err != nil {
+		return newCommandError("Error saving configuration", err)
+	}
+
+	if loaded, err := config.Load(); err == nil {
+		settings = loaded
+	}
+	return nil
+}
+
+// newManager creates a kubeconfig manager using the configured request timeout
+// and fallback namespaces
+func newManager() *kubeconfig.Manager {
+	return kubeconfig.NewManager(
+		kubeconfig.WithRequestTimeout(settings.Timeouts.Request.Duration()),
+		kubeconfig.WithFallbackNamespaces(settings.Namespaces.Fallback),
+	)
+}
+
+// configKeysDetails prints the valid configuration keys
+func configKeysDetails() {
+	names := make([]string, 0, len(config.Keys()))
+	for _, key := range config.Keys() {
+		names = append(names, fmt.Sprintf("%-24s %s", key.Name, key.Description))
+	}
+	ui.PrintList("Available keys:", names)
+}
+
+// configKeyError wraps errors about configuration keys, listing the valid keys when the key is unknown
+func configKeyError(msg string, err error) error {
+	cmdErr := &commandError{Msg: msg, Err: err}
+	if errors.Is(err, config.ErrUnknownKey) {
+		cmdErr.Details = configKeysDetails
+	}
+	return cmdErr
+}
+
+// configKeyCompletion completes configuration keys with their descriptions
+func configKeyCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var suggestions []string
+	for _, key := range config.Keys() {
+		suggestions = append(suggestions, key.Name+"\t"+key.Description)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change kontext's configuration",
+	Long: `View and change kontext's own configuration.
+
+The configuration is stored in $XDG_CONFIG_HOME/kontext/config.yaml
+(~/.config/kontext/config.yaml by default). Set KONTEXT_CONFIG to use
+another file. Every setting can also be overridden for a single run with
+its environment variable, for example KONTEXT_SELECTOR_SIZE=20.
+
+Examples:
+  # Show the effective configuration
+  kontext config view
+
+  # Read and change a single setting
+  kontext config get selector.size
+  kontext config set selector.size 20
+
+  # Open the file in $VISUAL or $EDITOR
+  kontext config edit`,
+}
+
+// configViewCmd represents the config view command
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the effective configuration",
+	Long: `Show the effective configuration as YAML, including the defaults and
+any environment variable overrides.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := yaml.Marshal(settings)
+		if err != nil {
+			return newCommandError("Error printing configuration", err)
+		}
+		fmt.Fprint(ui.Default().Out, string(data))
+		return nil
+	},
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print the effective value of a setting",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: configKeyCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := settings.Get(args[0])
+		if err != nil {
+			return configKeyError("Error reading setting", err)
+		}
+		fmt.Fprintln(ui.Default().Out, value)
+		return nil
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the configuration file",
+	Long: `Change a setting in the configuration file, creating the file if needed.
+
+Lists such as namespaces.fallback are given comma-separated and durations
+as 5s, 1m and so on.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: configKeyCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+
+		var saved string
+		err := saveConfig("setting", func(file *config.Config) error {
+			if err := file.Set(key, value); err != nil {
+				return configKeyError("Error changing setting", err)
+			}
+			saved, _ = file.Get(key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		ui.PrintSuccess(fmt.Sprintf("Set %s to", key), saved)
+
+		for _, k := range config.Keys() {
+			if strings.EqualFold(k.Name, key) && os.Getenv(k.Env) != "" {
+				ui.PrintWarning(fmt.Sprintf("%s is set and overrides this setting", k.Env))
+			}
+		}
+		return nil
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in your editor",
+	Long: `Open the configuration file in $VISUAL or $EDITOR (vi by default),
+creating it with the default settings if it doesn't exist. The file is
+validated when the editor exits.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireInput("An editor can't be opened"); err != nil {
+			return err
+		}
+
+		path := config.GetConfigPath()
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			if err := config.Default().Save(path); err != nil {
+				return newCommandError("Error creating configuration", err)
+			}
+		}
+
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+		}
+
+		fields := strings.Fields(editor)
+		editorCmd := exec.Command(fields[0], append(fields[1:], path)...)
+		editorCmd.Stdin = os.Stdin
+		editorCmd.Stdout = os.Stdout
+		editorCmd.Stderr = os.Stderr
+		if err := editorCmd.Run(); err != nil {
+			return newCommandError("Error running editor", err)
+		}
+
+		edited, err := config.LoadFile(path)
+		if err == nil {
+			err = edited.Validate()
+		}
+		if err != nil {
+			return &commandError{
+				Msg:     "The configuration is invalid",
+				Err:     err,
+				Details: func() { ui.PrintNote("Run 'kontext config edit' again to fix it") },
+			}
+		}
+
+		ui.PrintSuccess("Saved configuration", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configViewCmd, configGetCmd, configSetCmd, configEditCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
 	"github.com/user-cube/kontext/pkg/ui"
 )
 
@@ -14,17 +15,45 @@ var currentCmd = &cobra.Command{
 
 Examples:
   # Show the current active context
-  kontext current`,
-	Run: func(cmd *cobra.Command, args []string) {
-		currentContext, err := kubeconfig.GetCurrentContext()
+  kontext current
+
+  # Print only the name, for scripts
+  kontext current -o name
+
+  # Machine-readable output
+  kontext current -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		config, err := newManager().Config()
 		if err != nil {
-			ui.PrintError("Error retrieving current context", err, true)
+			return newCommandError("Error retrieving current context", err)
+		}
+
+		currentContext := config.CurrentContext
+		if currentContext == "" {
+			return newCommandError("Error retrieving current context", kubeconfig.ErrNoCurrentContext)
+		}
+
+		if !format.IsText() {
+			info := output.ContextInfo{Name: currentContext, Current: true}
+			if details, err := kubeconfig.DescribeContext(config, currentContext, false); err == nil {
+				info = output.NewContextInfo(details)
+			}
+			return printObject(format, output.CurrentContext{ContextInfo: info})
 		}
 
 		ui.PrintCurrentContext(currentContext)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(currentCmd)
+
+	// Add flags
+	addOutputFlag(currentCmd)
 }
@@ -1,111 +1,235 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/audit"
 	"github.com/user-cube/kontext/pkg/kubeconfig"
 	"github.com/user-cube/kontext/pkg/ui"
 )
 
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
-	Use:     "delete [context]",
+	Use:     "delete [context|pattern]...",
 	Aliases: []string{"rm"},
-	Short:   "Delete a Kubernetes context from your kubeconfig",
-	Long: `Delete a Kubernetes context from your kubeconfig file.
+	Short:   "Delete Kubernetes contexts from your kubeconfig",
+	Long: `Delete one or more Kubernetes contexts from your kubeconfig file.
 If no context name is provided, an interactive selector will be displayed.
 
+Arguments may be exact names or shell-style patterns (quote them so your
+shell doesn't expand them). With --regex they are regular expressions that
+must match the whole context name.
+
+With -t/--tag only contexts whose tags match are considered; without names
+or --multi, every matching context is deleted.
+
+Before anything is deleted, the full set of contexts, clusters and users that
+will be removed is shown and a single confirmation is requested. Deleting
+a protected context also requires --force.
+
 Examples:
   # Delete a context interactively
   kontext delete
 
+  # Pick several contexts from a checklist
+  kontext delete --multi
+
   # Delete a specific context by name
   kontext delete my-context
-  kontext rm my-context`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Load available contexts
-		contexts, err := kubeconfig.GetContexts()
+  kontext rm my-context
+
+  # Delete all preview environments
+  kontext delete 'pr-*'
+
+  # Delete using a regular expression
+  kontext delete --regex 'pr-[0-9]+'
+
+  # Show what would be deleted without changing anything
+  kontext delete 'pr-*' --dry-run
+
+  # Delete every context tagged env=preview
+  kontext delete -t env=preview
+
+  # Delete without confirmation (for scripts)
+  kontext delete 'pr-*' --yes
+
+  # Protected contexts also need --force
+  kontext delete prod-old --force`,
+	ValidArgsFunction: contextCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useRegex, _ := cmd.Flags().GetBool("regex")
+		multi, _ := cmd.Flags().GetBool("multi")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		m := newManager()
+		config, err := m.Config()
 		if err != nil {
-			ui.PrintError("Error retrieving contexts", err, true)
+			return newCommandError("Error loading kubeconfig", err)
 		}
 
-		if len(contexts) == 0 {
+		if len(config.Contexts) == 0 {
 			ui.PrintWarning("No contexts found in kubeconfig")
-			return
+			return nil
 		}
 
-		currentContext, err := kubeconfig.GetCurrentContext()
-		if err != nil {
-			ui.PrintError("Error retrieving current context", err, true)
-		}
+		currentContext := config.CurrentContext
 
-		var contextName string
+		// Prioritize current context at the top
+		contextNames := make([]string, 0, len(config.Contexts))
+		for name := range config.Contexts {
+			contextNames = append(contextNames, name)
+		}
+		contextNames = ui.SortContexts(contextNames, currentContext, settings.Selector.CurrentFirst)
 
-		if len(args) == 0 {
-			// Interactive selection if no context name is provided
-			contextNames := make([]string, 0, len(contexts))
-			for name := range contexts {
-				contextNames = append(contextNames, name)
-			}
+		tagged, _ := cmd.Flags().GetString("tag")
+		if contextNames, err = filterByTags(cmd, contextNames); err != nil {
+			return err
+		}
 
-			// Prioritize current context at the top
-			contextNames = ui.SortContexts(contextNames, currentContext, true)
+		var toDelete []string
 
+		switch {
+		case len(args) > 0:
+			patterns := make([]string, 0, len(args))
+			for _, arg := range args {
+				if !useRegex && !kubeconfig.IsPattern(arg) {
+					arg = resolveContextArg(config, arg)
+				}
+				patterns = append(patterns, arg)
+			}
+			toDelete, err = kubeconfig.MatchContexts(contextNames, patterns, useRegex)
+			if errors.Is(err, kubeconfig.ErrContextNotFound) || errors.Is(err, kubeconfig.ErrNoMatchingContexts) {
+				return &commandError{
+					Msg:     "Error deleting contexts",
+					Err:     err,
+					Details: func() { ui.PrintContextList(ui.SortContexts(contextNames, currentContext, false), currentContext) },
+				}
+			}
+			if err != nil {
+				// An invalid pattern is a usage error
+				return err
+			}
+		case tagged != "" && !multi:
+			toDelete = append(toDelete, contextNames...)
+			sort.Strings(toDelete)
+		case multi:
+			if err := requireInput("Context names or patterns are required"); err != nil {
+				return err
+			}
+			indices, err := ui.MultiSelect("Select contexts to delete:", contextNames, false)
+			if err != nil {
+				return newCommandError("Context selection failed", err)
+			}
+			for _, i := range indices {
+				toDelete = append(toDelete, contextNames[i])
+			}
+			if len(toDelete) == 0 {
+				ui.PrintWarning("No contexts selected")
+				return nil
+			}
+		default:
+			if err := requireInput("Context names or patterns are required"); err != nil {
+				return err
+			}
+			// Interactive selection if no context name is provided
 			selector := ui.CreateContextSelector(contextNames, currentContext)
-			_, selection, err := selector.Run()
+			selection, err := ui.RunSelector(selector)
 			if err != nil {
-				ui.PrintError("Selection canceled", err, false)
-				return
+				return newCommandError("Context selection failed", err)
 			}
+			toDelete = []string{selection}
+		}
 
-			contextName = selection
-		} else {
-			contextName = args[0]
-
-			// Validate that the context exists
-			if _, exists := contexts[contextName]; !exists {
-				contextNames := make([]string, 0, len(contexts))
-				for name := range contexts {
-					contextNames = append(contextNames, name)
-				}
-				contextNames = ui.SortContexts(contextNames, currentContext, false)
+		// Show everything that will be removed
+		clusters, users := kubeconfig.DeletionPlan(config, toDelete)
+		printDeletionPlan(toDelete, clusters, users)
 
-				ui.PrintError(fmt.Sprintf("Context '%s' does not exist", contextName), nil, false)
-				ui.PrintContextList(contextNames, currentContext)
-				os.Exit(1)
+		// Warn if deleting the current context
+		for _, name := range toDelete {
+			if name == currentContext {
+				ui.PrintWarning(
+					fmt.Sprintf("You are about to delete the current context '%s'", name),
+					"(current context will be unset)",
+				)
 			}
 		}
 
-		// Warn if deleting the current context
-		if contextName == currentContext {
-			ui.PrintWarning(
-				fmt.Sprintf("You are about to delete the current context '%s'", contextName),
-				"(current context will be unset)",
-			)
+		protected := settings.ProtectedContexts(toDelete)
+		for _, name := range protected {
+			ui.PrintWarning(fmt.Sprintf("Context '%s' is protected", name))
 		}
 
-		// Ask for confirmation before deleting
-		confirmed, err := ui.ConfirmAction(fmt.Sprintf("Delete context '%s' from kubeconfig?", contextName))
-		if err != nil {
-			ui.PrintError("Error during confirmation", err, true)
+		if dryRun {
+			ui.PrintNote("Dry run, nothing was deleted")
+			return nil
 		}
-		if !confirmed {
-			ui.PrintWarning("Context deletion canceled", contextName)
-			return
+
+		if err := requireForce(cmd, protected, "delete protected context"); err != nil {
+			return err
+		}
+
+		// Ask for confirmation before deleting
+		if !yes {
+			if err := requireInput("Confirmation required, pass --yes to delete without prompting"); err != nil {
+				return err
+			}
+
+			prompt := fmt.Sprintf("Delete context '%s' from kubeconfig?", toDelete[0])
+			if len(toDelete) > 1 {
+				prompt = fmt.Sprintf("Delete %d contexts from kubeconfig?", len(toDelete))
+			}
+
+			confirmed, err := ui.ConfirmAction(prompt)
+			if err != nil {
+				return newCommandError("Error during confirmation", err)
+			}
+			if !confirmed {
+				ui.PrintWarning("Context deletion canceled", strings.Join(toDelete, ", "))
+				return nil
+			}
 		}
 
 		// Perform deletion
-		if err := kubeconfig.DeleteContext(contextName); err != nil {
-			ui.PrintError("Error deleting context", err, true)
+		if err := m.DeleteContexts(toDelete); err != nil {
+			return newCommandError("Error deleting contexts", err)
 		}
+		auditRemoval(m, audit.OpDelete, toDelete, clusters, users)
 
-		ui.PrintSuccess("Deleted context", contextName)
+		for _, name := range toDelete {
+			ui.PrintSuccess("Deleted context", name)
+		}
+		return nil
 	},
 }
 
+// printDeletionPlan lists the contexts, clusters and users that will be removed
+func printDeletionPlan(contexts, clusters, users []string) {
+	items := make([]string, 0, len(contexts)+len(clusters)+len(users))
+	for _, name := range contexts {
+		items = append(items, "context "+name)
+	}
+	for _, name := range clusters {
+		items = append(items, "cluster "+name)
+	}
+	for _, name := range users {
+		items = append(items, "user    "+name)
+	}
+	ui.PrintList("The following entries will be removed:", items)
+}
+
 func init() {
 	rootCmd.AddCommand(deleteCmd)
-}
 
+	// Add flags
+	deleteCmd.Flags().Bool("regex", false, "Treat arguments as regular expressions")
+	deleteCmd.Flags().BoolP("multi", "m", false, "Select several contexts from an interactive checklist")
+	deleteCmd.Flags().Bool("dry-run", false, "Show what would be deleted without changing anything")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+	addForceFlag(deleteCmd, "Allow deleting protected contexts")
+	addTagFlag(deleteCmd)
+}
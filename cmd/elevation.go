@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/hooks"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// addForFlag registers the --for flag of time-boxed switches
+func addForFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration("for", 0, "Switch back to the previous context and namespace after this long, e.g. 30m")
+}
+
+// getForDuration returns the --for duration
+// A negative duration is a usage error.
+func getForDuration(cmd *cobra.Command) (time.Duration, error) {
+	duration, _ := cmd.Flags().GetDuration("for")
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid --for duration %s, it must be positive", duration)
+	}
+	return duration, nil
+}
+
+// updateElevation records or ends a time-boxed switch after applySwitch selected contextName
+//
+// With --for a new time box starts, going back to the previous context when
+// it expires; chained time boxes keep the fallback of the first one. Switching
+// to the elevated context again extends it, and switching anywhere else
+// without --for ends it.
+func updateElevation(cmd *cobra.Command, m *kubeconfig.Manager, contextName, previousContext, previousNamespace string) {
+	duration, _ := getForDuration(cmd)
+
+	existing, err := history.LoadElevation()
+	if err != nil {
+		ui.PrintWarning("Could not read the time-boxed switch", err.Error())
+	}
+
+	if duration == 0 {
+		if existing != nil && existing.Context != contextName {
+			clearElevation()
+		}
+		return
+	}
+
+	elevation := history.Elevation{
+		Context:           contextName,
+		Expires:           m.Now().Add(duration),
+		FallbackContext:   previousContext,
+		FallbackNamespace: previousNamespace,
+	}
+	if existing != nil {
+		elevation.FallbackContext = existing.FallbackContext
+		elevation.FallbackNamespace = existing.FallbackNamespace
+	}
+	if elevation.FallbackContext == "" || elevation.FallbackContext == contextName {
+		ui.PrintWarning("Ignoring --for, there is no other context to switch back to")
+		return
+	}
+
+	if err := history.SaveElevation(elevation); err != nil {
+		ui.PrintWarning("Could not record the time-boxed switch", err.Error())
+		return
+	}
+	ui.PrintNote(fmt.Sprintf("Switching back to %s at %s", elevation.FallbackContext, elevation.Expires.Local().Format("15:04")),
+		fmt.Sprintf("(in %s)", duration))
+}
+
+// revertExpiredElevation switches back to the fallback context once a time-boxed switch expires
+// It runs before every command; failures are reported but never stop the command.
+// The prompt segment reverts too, so the fallback shows up in the prompt; the
+// hook and env commands the shell integration runs on every directory change
+// leave it to the next command.
+func revertExpiredElevation(cmd *cobra.Command) {
+	if cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd {
+		return
+	}
+	if cmd == hookCmd || cmd == envCmd {
+		return
+	}
+
+	elevation, err := history.LoadElevation()
+	if err != nil {
+		ui.PrintWarning("Could not read the time-boxed switch", err.Error())
+		return
+	}
+	m := newManager()
+	if elevation == nil || !elevation.Expired(m.Now()) {
+		return
+	}
+
+	currentContext, err := m.GetCurrentContext()
+	if err != nil {
+		ui.PrintWarning("Could not end the time-boxed switch", err.Error())
+		return
+	}
+	defer clearElevation()
+
+	// The user already moved on, for example with kubectl config use-context,
+	// or unset the current context
+	if currentContext == "" || currentContext != elevation.Context {
+		ui.Debugf("time-boxed switch to %s expired after the context changed to %q", elevation.Context, currentContext)
+		return
+	}
+
+	currentNamespace, _ := m.GetCurrentNamespace()
+	namespace := elevation.FallbackNamespace
+	if namespace == "" {
+		namespace, _ = m.GetNamespaceForContext(elevation.FallbackContext)
+	}
+
+	// Going back is never vetoed, a failing pre hook is only reported
+	change := hooks.Change{
+		Kind:         "context",
+		OldContext:   currentContext,
+		OldNamespace: currentNamespace,
+		NewContext:   elevation.FallbackContext,
+		NewNamespace: namespace,
+	}
+	if err := runHooks(config.PreHook, change); err != nil {
+		ui.PrintWarning("A pre-switch hook failed", err.Error())
+	}
+
+	// Restore the context and namespace with a single write
+	kubeConfig, _ := m.Config()
+	fallback, exists := kubeConfig.Contexts[elevation.FallbackContext]
+	if !exists {
+		ui.PrintWarning(fmt.Sprintf("Time-boxed switch to '%s' expired but switching back failed", elevation.Context),
+			fmt.Sprintf("context not found: '%s'", elevation.FallbackContext))
+		return
+	}
+	if current, _ := m.GetNamespaceForContext(elevation.FallbackContext); current != namespace {
+		fallback.Namespace = namespace
+	}
+	kubeConfig.CurrentContext = elevation.FallbackContext
+	if err := m.Save(); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Time-boxed switch to '%s' expired but switching back failed", elevation.Context), err.Error())
+		return
+	}
+
+	recordSwitch(m, elevation.FallbackContext, namespace, currentContext, currentNamespace)
+	auditChange(m, change, "expired")
+	runPostHooks(change)
+	ui.PrintWarning(fmt.Sprintf("Time-boxed switch to '%s' expired, switched back to", elevation.Context),
+		fmt.Sprintf("%s (namespace %s)", elevation.FallbackContext, namespace))
+}
+
+// clearElevation ends the time-boxed switch
+func clearElevation() {
+	if err := history.ClearElevation(); err != nil {
+		ui.PrintWarning("Could not clear the time-boxed switch", err.Error())
+	}
+}
+
+// currentExpiry returns the time-boxed switch of contextName, or nil if it isn't time-boxed
+func currentExpiry(contextName string) *history.Elevation {
+	elevation, err := history.LoadElevation()
+	if err != nil || elevation == nil || elevation.Context != contextName {
+		return nil
+	}
+	return elevation
+}
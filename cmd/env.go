@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/shell"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// addShellFlag registers the --shell flag of commands printing shell statements
+func addShellFlag(cmd *cobra.Command) {
+	cmd.Flags().String("shell", "", "Shell to print statements for: bash, zsh or fish (default from $SHELL)")
+}
+
+// getShell returns the shell selected with --shell or detected from $SHELL
+func getShell(cmd *cobra.Command) (string, error) {
+	name, _ := cmd.Flags().GetString("shell")
+	if name == "" {
+		return shell.Detect(), nil
+	}
+	return name, shell.Validate(name)
+}
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env [context]",
+	Short: "Print the environment variables of a context as shell statements",
+	Long: `Print export statements for the environment variables configured for a
+context (the current one by default), and unset statements for the variables
+a previous run exported that the context doesn't set.
+
+The shell integration (see 'kontext init') applies them before every prompt,
+so variables such as AWS_PROFILE follow the current context.
+
+Examples:
+  # Show what would be exported for the current context
+  kontext env
+
+  # Apply the variables of the current context by hand
+  eval "$(kontext env --shell bash)"
+  kontext env --shell fish | source`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: contextCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shellName, err := getShell(cmd)
+		if err != nil {
+			return err
+		}
+
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+
+		contextName := kubeConfig.CurrentContext
+		if len(args) > 0 {
+			contextName = resolveContextArg(kubeConfig, args[0])
+			if _, exists := kubeConfig.Contexts[contextName]; !exists {
+				return newCommandError("Error reading environment",
+					fmt.Errorf("%w: '%s'", kubeconfig.ErrContextNotFound, contextName))
+			}
+		}
+
+		// Without a current context, only the previous variables are unset
+		vars := map[string]string{}
+		if contextName != "" {
+			vars = settings.EnvFor(contextName)
+		} else {
+			ui.Debugf("no current context, unsetting the environment")
+		}
+
+		fmt.Fprint(ui.Default().Out, shell.EnvScript(shellName, os.Getenv(shell.ManagedEnv), vars))
+		return nil
+	},
+}
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:       "init <shell>",
+	Short:     "Print the shell integration script",
+	ValidArgs: shell.Shells,
+	Long: `Print the shell integration for bash, zsh or fish.
+
+It runs 'kontext hook' before every prompt, which applies the nearest
+allowed .kontext file and the environment variables of the current context
+(see 'kontext env'), so both follow context switches and directory changes.
+
+Examples:
+  # bash (~/.bashrc)
+  eval "$(kontext init bash)"
+
+  # zsh (~/.zshrc)
+  eval "$(kontext init zsh)"
+
+  # fish (~/.config/fish/config.fish)
+  kontext init fish | source`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := shell.Validate(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprint(ui.Default().Out, shell.Init(args[0]))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd, initCmd)
+
+	// Add flags
+	addShellFlag(envCmd)
+}
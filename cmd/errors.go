@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// Exit codes returned by kontext
+//
+// They are part of the CLI's interface and documented in the README, so
+// wrappers can tell failures apart. Don't renumber them.
+const (
+	exitOK                 = 0   // Success
+	exitError              = 1   // Any other error (including kontext lint finding errors)
+	exitUsage              = 2   // Invalid arguments, flags or configuration
+	exitKubeConfig         = 3   // The kubeconfig can't be loaded or saved
+	exitNotFound           = 4   // A context, cluster or user does not exist, or a pattern matched nothing
+	exitNoCurrentContext   = 5   // The command needs a current context but none is set
+	exitClusterUnreachable = 6   // The cluster of the context could not be contacted
+	exitNoInput            = 7   // A prompt was required but interactive input is disabled
+	exitCanceled           = 130 // An interactive selection or confirmation was canceled
+)
+
+// commandError is an error returned by a command's RunE
+//
+// Msg is the human-readable summary printed before the underlying error and
+// details, if set, prints additional help after it (for example the list of
+// available contexts).
+//
+// Commands return plain errors only for invalid usage; every other failure is
+// wrapped in a commandError so it can be told apart from cobra's own argument
+// and flag errors.
+type commandError struct {
+	Msg     string
+	Err     error
+	Details func()
+}
+
+// Error implements the error interface
+func (e *commandError) Error() string {
+	if e.Err == nil {
+		return e.Msg
+	}
+	return e.Msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *commandError) Unwrap() error {
+	return e.Err
+}
+
+// newCommandError wraps err with a human-readable message
+// err may be nil when the message says it all.
+func newCommandError(msg string, err error) error {
+	return &commandError{Msg: msg, Err: err}
+}
+
+// exitCode maps an error returned by a command to the process exit code
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, ui.ErrSelectionCanceled):
+		return exitCanceled
+	case errors.Is(err, ui.ErrNoInput):
+		return exitNoInput
+	case errors.Is(err, kubeconfig.ErrNoCurrentContext):
+		return exitNoCurrentContext
+	case errors.Is(err, kubeconfig.ErrContextNotFound),
+		errors.Is(err, kubeconfig.ErrClusterNotFound),
+		errors.Is(err, kubeconfig.ErrUserNotFound),
+		errors.Is(err, kubeconfig.ErrNoMatchingContexts),
+		errors.Is(err, config.ErrAliasNotFound):
+		return exitNotFound
+	case errors.Is(err, kubeconfig.ErrClusterUnreachable):
+		return exitClusterUnreachable
+	case errors.Is(err, config.ErrInvalidConfig),
+		errors.Is(err, config.ErrUnknownKey):
+		return exitUsage
+	case errors.Is(err, kubeconfig.ErrKubeConfigLoad),
+		errors.Is(err, kubeconfig.ErrKubeConfigSave):
+		return exitKubeConfig
+	}
+
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) {
+		return exitError
+	}
+	return exitUsage
+}
+
+// printCommandError prints an error returned by cmd to stderr
+func printCommandError(cmd *cobra.Command, err error) {
+	var cmdErr *commandError
+	if !errors.As(err, &cmdErr) {
+		ui.PrintError(err.Error(), nil)
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		return
+	}
+
+	ui.PrintError(cmdErr.Msg, cmdErr.Err)
+	if cmdErr.Details != nil {
+		cmdErr.Details()
+	}
+}
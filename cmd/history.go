@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// getNamespaces lists the namespaces of a context and records whether its
+// cluster was reachable, so `kontext prune` can find long-dead clusters.
+// If the cluster can't be queried, a set of default namespaces is returned.
+func getNamespaces(m *kubeconfig.Manager, contextName string) []string {
+	namespaces, err := m.ListNamespacesForContext(contextName)
+	if err == nil || errors.Is(err, kubeconfig.ErrClusterUnreachable) {
+		recordProbe(m, contextName, err)
+	}
+	if err != nil {
+		return settings.Namespaces.Fallback
+	}
+	return namespaces
+}
+
+// recordProbe stores a probe result in the history, at the manager's time
+// Failing to record history never interrupts the command.
+func recordProbe(m *kubeconfig.Manager, contextName string, probeErr error) {
+	if err := history.RecordProbe(contextName, probeErr, m.Now()); err != nil {
+		ui.PrintWarning("Could not record probe history", err.Error())
+	}
+}
+
+// recordNamespace remembers the namespace chosen in a context
+// Failing to record it never interrupts the command.
+func recordNamespace(contextName, namespace string) {
+	if err := history.RecordNamespace(contextName, namespace); err != nil {
+		ui.PrintWarning("Could not record the namespace", err.Error())
+	}
+}
+
+// lastNamespace returns the namespace last chosen in a context, or "" if unknown
+func lastNamespace(contextName string) string {
+	namespace, err := history.LastNamespace(contextName)
+	if err != nil {
+		ui.Debugf("could not read the last used namespaces: %v", err)
+	}
+	return namespace
+}
+
+// recordSwitch appends a context or namespace change to the switch history, at the manager's time
+// Failing to record history never interrupts the command.
+func recordSwitch(m *kubeconfig.Manager, contextName, namespace, previousContext, previousNamespace string) {
+	event := history.Event{
+		Time:              m.Now(),
+		Context:           contextName,
+		Namespace:         namespace,
+		PreviousContext:   previousContext,
+		PreviousNamespace: previousNamespace,
+	}
+	if err := history.RecordSwitch(event); err != nil {
+		ui.PrintWarning("Could not record switch history", err.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/hooks"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// runHooks runs the configured hooks of a phase for a change, stopping at the first failure
+func runHooks(phase string, change hooks.Change) error {
+	runner := hooks.NewRunner(ui.IsInteractive())
+	for _, hook := range settings.HooksFor(phase, change.NewContext) {
+		timeout := settings.HookTimeout(hook).Duration()
+		ui.Debugf("running %s hook (timeout %s): %s", phase, timeout, hook.Run)
+		if err := runner.Run(hook.Run, phase, change, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPreHooks runs the pre hooks of a change; a failing hook vetoes the change
+func runPreHooks(change hooks.Change) error {
+	if err := runHooks(config.PreHook, change); err != nil {
+		return newCommandError(fmt.Sprintf("A pre-switch hook prevented the %s change", change.Kind), err)
+	}
+	return nil
+}
+
+// runPostHooks runs the post hooks of a change
+// The change already happened, so failures are only reported.
+func runPostHooks(change hooks.Change) {
+	if err := runHooks(config.PostHook, change); err != nil {
+		ui.PrintWarning("A post-switch hook failed", err.Error())
+	}
+}
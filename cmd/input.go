@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// noInputEnv disables interactive prompts when set to a true value
+const noInputEnv = "KONTEXT_NO_INPUT"
+
+// configureInput disables interactive prompts, colors and unicode glyphs when
+// kontext runs without a terminal (for example in CI) or when input is
+// disabled with --no-input or KONTEXT_NO_INPUT
+func configureInput(noInput bool) {
+	if noInput || envBool(noInputEnv) || !ui.IsTerminal() {
+		ui.SetInteractive(false)
+		ui.SetColor(false)
+		ui.SetASCII(true)
+	}
+}
+
+// envBool reports whether an environment variable is set to a true value
+// Any non-empty value that isn't a recognized boolean counts as true.
+func envBool(name string) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	return err != nil || enabled
+}
+
+// requireInput returns an error explaining what to pass instead of a prompt
+// when interactive input is disabled
+func requireInput(msg string) error {
+	if ui.IsInteractive() {
+		return nil
+	}
+	return newCommandError(msg, ui.ErrNoInput)
+}
+
+// configureVerbosity sets the verbosity of the output from the --quiet and --verbose flags
+// Verbose mode also logs which kubeconfig files were loaded and how long cluster calls took.
+func configureVerbosity(quiet, verbose bool) {
+	switch {
+	case quiet:
+		ui.Default().SetVerbosity(ui.VerbosityQuiet)
+	case verbose:
+		ui.Default().SetVerbosity(ui.VerbosityVerbose)
+		kubeconfig.SetLogf(ui.Debugf)
+	default:
+		ui.Default().SetVerbosity(ui.VerbosityNormal)
+	}
+}
+
+// configureOutput applies the color and ASCII settings
+// They run after configureInput, so --color=always also colors redirected output.
+func configureOutput(colorValue string, ascii bool) error {
+	mode, err := ui.ParseColorMode(colorValue)
+	if err != nil {
+		return err
+	}
+	ui.SetColorMode(mode)
+
+	if ascii {
+		ui.SetASCII(true)
+	}
+	return nil
+}
+
+// persistentPreRunE configures the input mode, colors and verbosity from the
+// global flags and the user configuration; flags take precedence
+// It also ends an expired time-boxed switch before the command runs.
+func persistentPreRunE(cmd *cobra.Command, args []string) error {
+	noInput, _ := cmd.Flags().GetBool("no-input")
+	configureInput(noInput)
+
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	configureVerbosity(quiet, verbose)
+
+	if err := loadSettings(cmd); err != nil {
+		return err
+	}
+
+	colorValue := settings.Color
+	if cmd.Flags().Changed("color") {
+		colorValue, _ = cmd.Flags().GetString("color")
+	}
+	ascii, _ := cmd.Flags().GetBool("ascii")
+	if err := configureOutput(colorValue, ascii || settings.ASCII); err != nil {
+		return err
+	}
+
+	revertExpiredElevation(cmd)
+	return nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/audit"
+	"github.com/user-cube/kontext/pkg/hooks"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:     "lint",
+	Aliases: []string{"validate"},
+	Short:   "Validate your kubeconfig and report broken entries",
+	Long: `Validate your kubeconfig and report broken or risky entries.
+
+The following problems are reported:
+  error    contexts referencing missing clusters or users
+  error    certificate, key or token files that do not exist
+  error    exec plugins whose command is not on PATH
+  error    a current-context that does not exist
+  warning  clusters and users not referenced by any context
+  warning  clusters using insecure-skip-tls-verify
+  info     clusters sharing the same server URL
+
+With --fix, safe repairs are applied: orphan clusters and users are removed
+and a dangling current-context is unset. The command exits with status 1 when
+errors remain, so it can be used in CI.
+
+Examples:
+  # Validate the kubeconfig
+  kontext lint
+
+  # Apply safe repairs
+  kontext lint --fix
+
+  # Machine-readable output for CI
+  kontext validate -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Repairs are applied to the same snapshot that is then validated
+		m := newManager()
+
+		var fixed []kubeconfig.Issue
+		if fix {
+			config, err := m.Config()
+			if err != nil {
+				return newCommandError("Error fixing kubeconfig", err)
+			}
+			previousContext := config.CurrentContext
+
+			fixed, err = m.Fix()
+			if err != nil {
+				return newCommandError("Error fixing kubeconfig", err)
+			}
+			auditFix(m, fixed, previousContext)
+		}
+
+		issues, err := m.Lint()
+		if err != nil {
+			return newCommandError("Error validating kubeconfig", err)
+		}
+
+		errorCount := 0
+		for _, issue := range issues {
+			if issue.Severity == kubeconfig.SeverityError {
+				errorCount++
+			}
+		}
+
+		if !format.IsText() {
+			report := output.LintReport{Issues: issues, Fixed: fixed, Errors: errorCount}
+			if report.Issues == nil {
+				report.Issues = []kubeconfig.Issue{}
+			}
+			if report.Fixed == nil {
+				report.Fixed = []kubeconfig.Issue{}
+			}
+			if err := printObject(format, report); err != nil {
+				return err
+			}
+		} else {
+			for _, issue := range fixed {
+				ui.PrintSuccess("Fixed", fmt.Sprintf("%s %s", issue.Kind, issue.Name), issue.Message)
+			}
+			for _, issue := range issues {
+				ui.PrintIssue(string(issue.Severity), fmt.Sprintf("%s %s", issue.Kind, issue.Name), issue.Message, issue.Code)
+			}
+			if len(issues) == 0 {
+				ui.PrintSuccess("Kubeconfig is valid")
+			} else if !fix && hasFixable(issues) {
+				ui.PrintNote("Some issues can be repaired automatically", "(run kontext lint --fix)")
+			}
+		}
+
+		if errorCount > 0 {
+			return newCommandError(fmt.Sprintf("Kubeconfig has %d errors", errorCount), nil)
+		}
+		return nil
+	},
+}
+
+// auditFix records the repairs of lint --fix in the audit log: the removed
+// clusters and users, and unsetting a current context that doesn't exist
+func auditFix(m *kubeconfig.Manager, fixed []kubeconfig.Issue, previousContext string) {
+	var clusters, users []string
+	unset := false
+	for _, issue := range fixed {
+		switch issue.Code {
+		case kubeconfig.IssueOrphanCluster:
+			clusters = append(clusters, issue.Name)
+		case kubeconfig.IssueOrphanUser:
+			users = append(users, issue.Name)
+		case kubeconfig.IssueMissingCurrentContext:
+			unset = true
+		}
+	}
+
+	if len(clusters) > 0 || len(users) > 0 {
+		auditRemoval(m, audit.OpLint, nil, clusters, users)
+	}
+	if unset {
+		auditChange(m, hooks.Change{Kind: "context", OldContext: previousContext}, "lint --fix")
+	}
+}
+
+// hasFixable reports whether any of the issues can be repaired with --fix
+func hasFixable(issues []kubeconfig.Issue) bool {
+	for _, issue := range issues {
+		if issue.Fixable {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	// Add flags
+	lintCmd.Flags().Bool("fix", false, "Apply safe repairs (remove orphan clusters/users, unset a missing current-context)")
+	addOutputFlag(lintCmd)
+}
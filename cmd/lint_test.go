@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/user-cube/kontext/pkg/audit"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestAuditFix(t *testing.T) {
+	s := config.Default()
+	s.Audit.Enabled = true
+	s.Audit.Path = filepath.Join(t.TempDir(), "audit.jsonl")
+	useSettings(t, s)
+
+	kubeConfig := showTestConfig()
+	kubeConfig.Clusters["orphan"] = &api.Cluster{Server: "https://orphan.example.com"}
+	kubeConfig.AuthInfos["orphan"] = &api.AuthInfo{Token: "orphan-token"}
+	kubeConfig.CurrentContext = "gone"
+	m := kubeconfig.NewManager(
+		kubeconfig.WithConfig(kubeConfig),
+		kubeconfig.WithWriter(kubeconfig.WriterFunc(func(*api.Config) error { return nil })),
+	)
+
+	fixed, err := m.Fix()
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	auditFix(m, fixed, "gone")
+
+	entries, err := auditLog().Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("audit log has %d entries, want 2: %+v", len(entries), entries)
+	}
+
+	removal := entries[0]
+	if removal.Operation != audit.OpLint ||
+		!reflect.DeepEqual(removal.Clusters, []string{"orphan"}) || !reflect.DeepEqual(removal.Users, []string{"orphan"}) {
+		t.Errorf("removal entry = %+v, want lint removing cluster and user orphan", removal)
+	}
+
+	unset := entries[1]
+	if unset.Operation != audit.OpSwitch || unset.OldContext != "gone" || unset.NewContext != "" || unset.Reason != "lint --fix" {
+		t.Errorf("unset entry = %+v, want a switch from gone to no context", unset)
+	}
+}
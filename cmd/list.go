@@ -1,10 +1,8 @@
 package cmd
 
 import (
-	"sort"
-
 	"github.com/spf13/cobra"
-	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
 	"github.com/user-cube/kontext/pkg/ui"
 )
 
@@ -16,30 +14,72 @@ var listCmd = &cobra.Command{
 
 Examples:
   # List all available contexts with the current one highlighted
-  kontext list`,
-	Run: func(cmd *cobra.Command, args []string) {
-		contexts, err := kubeconfig.GetContexts()
+  kontext list
+
+  # Show cluster, server, user, namespace and auth type columns
+  kontext list -o wide
+
+  # Only production contexts, grouped by region
+  kontext list -t env=prod --group-by region
+
+  # Machine-readable output
+  kontext list -o json
+  kontext list -o name
+  kontext list -o go-template='{{range .contexts}}{{.name}} {{.server}}{{"\n"}}{{end}}'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		config, err := newManager().Config()
 		if err != nil {
-			ui.PrintError("Error retrieving contexts", err, true)
+			return newCommandError("Error retrieving contexts", err)
 		}
 
-		currentContext, err := kubeconfig.GetCurrentContext()
+		// Contexts are sorted by name for consistent display
+		contexts := contextInfos(config)
+
+		selector, err := getTagSelector(cmd)
 		if err != nil {
-			ui.PrintError("Error retrieving current context", err, true)
+			return err
+		}
+		if !selector.Empty() {
+			matched := contexts[:0]
+			for _, ctx := range contexts {
+				if selector.Matches(ctx.Tags) {
+					matched = append(matched, ctx)
+				}
+			}
+			contexts = matched
+		}
+
+		if !format.IsText() {
+			return printObject(format, output.ContextList{CurrentContext: config.CurrentContext, Contexts: contexts})
+		}
+
+		if len(contexts) == 0 && !selector.Empty() {
+			ui.PrintNote("No contexts match the tags", selector.String())
+			return nil
 		}
 
-		// Sort context names for consistent display
 		contextNames := make([]string, 0, len(contexts))
-		for name := range contexts {
-			contextNames = append(contextNames, name)
+		for _, ctx := range contexts {
+			contextNames = append(contextNames, ctx.Name)
 		}
-		sort.Strings(contextNames)
 
 		// Print contexts using the UI package
-		ui.PrintContextList(contextNames, currentContext)
+		applyGroupBy(cmd, contextNames)
+		ui.PrintContextList(contextNames, config.CurrentContext)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+
+	// Add flags
+	addOutputFlag(listCmd)
+	addTagFlag(listCmd)
+	addGroupByFlag(listCmd)
 }
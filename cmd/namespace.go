@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/hooks"
 	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
 	"github.com/user-cube/kontext/pkg/ui"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // nsCmd represents the namespace command
@@ -26,114 +32,224 @@ Examples:
   # Show current namespace without interactive selector
   kontext namespace --show
   kontext ns -s
+
+  # Show the current namespace as JSON or just its name
+  kontext ns -s -o json
+  kontext ns -s -o name
   
   # Switch to a specific namespace directly
   kontext namespace my-namespace
   kontext ns my-namespace
   
+  # Change the namespace of a protected context
+  kontext ns my-namespace --force
+
+  # Create the namespace if it doesn't exist, then switch to it
+  kontext ns my-feature --create-namespace
+
   # Typical workflow: switch context, then namespace
   kontext switch my-context
   kontext ns my-namespace`,
-	Run: runNamespace,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNamespace(cmd, newManager(), args)
+	},
 }
 
-// Function to run the namespace command
-func runNamespace(cmd *cobra.Command, args []string) {
+// runNamespace contains the main logic for the namespace command
+func runNamespace(cmd *cobra.Command, m *kubeconfig.Manager, args []string) error {
+	format, err := getOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Get current context and namespace
-	currentContext, err := kubeconfig.GetCurrentContext()
+	currentContext, err := m.GetCurrentContext()
 	if err != nil {
-		ui.PrintError("Error retrieving current context", err, true)
+		return newCommandError("Error retrieving current context", err)
 	}
 
-	currentNamespace, err := kubeconfig.GetCurrentNamespace()
+	currentNamespace, err := m.GetCurrentNamespace()
 	if err != nil {
-		ui.PrintError("Error retrieving current namespace", err, true)
+		return newCommandError("Error retrieving current namespace", err)
 	}
 
 	// If no arguments are provided, show the current namespace or interactive selector
 	if len(args) == 0 {
-		// Check if a specific flag was provided to only show the current value
-		if showOnly, _ := cmd.Flags().GetBool("show"); showOnly {
-			ui.PrintCurrentNamespace(currentContext, currentNamespace)
-			return
+		if create, _ := cmd.Flags().GetBool("create-namespace"); create {
+			return fmt.Errorf("--create-namespace requires a namespace name")
 		}
 
-		// Get available namespaces
-		namespaces, err := kubeconfig.GetNamespaces()
-		if err != nil {
-			ui.PrintError("Error retrieving namespaces", err, true)
+		// A structured output format implies --show
+		if !format.IsText() {
+			return printObject(format, output.NamespaceInfo{Context: currentContext, Namespace: currentNamespace})
 		}
 
-		// Check if we have namespaces to display
-		if len(namespaces) == 0 {
-			ui.PrintWarning("No namespaces available for context", currentContext)
-			return
+		// Check if a specific flag was provided to only show the current value
+		if showOnly, _ := cmd.Flags().GetBool("show"); showOnly {
+			ui.PrintCurrentNamespace(currentContext, currentNamespace)
+			return nil
 		}
 
-		// Sort namespaces and prioritize the current namespace
-		namespaces = ui.SortNamespaces(namespaces, currentNamespace, true)
-
-		// Create an interactive selector
-		selector := ui.CreateNamespaceSelector(namespaces, currentNamespace, currentContext)
-		_, selection, err := selector.Run()
+		if err := requireForce(cmd, []string{currentContext}, "change the namespace of protected context"); err != nil {
+			return err
+		}
 
-		if err != nil {
-			ui.PrintError("Selection canceled", err, false)
-			return
+		selection, err := selectNamespace(m, currentContext, currentNamespace)
+		if err != nil || selection == "" {
+			return err
 		}
 
 		// If the selected namespace is the same as the current one, don't do anything
 		if selection == currentNamespace {
 			ui.PrintWarning(fmt.Sprintf("Namespace '%s' is already selected", selection))
-			return
-		}
-
-		// Change the namespace
-		err = kubeconfig.SetNamespace(selection)
-		if err != nil {
-			ui.PrintError("Error setting namespace", err, true)
+			return nil
 		}
 
-		ui.PrintSuccess("Switched to namespace", selection, fmt.Sprintf("in context %s", currentContext))
-		return
+		return setNamespace(cmd, m, currentContext, currentNamespace, selection, false)
 	}
 
 	// Change to the specified namespace
-	namespace := args[0]
+	return switchNamespace(cmd, m, currentContext, currentNamespace, args[0])
+}
+
+// selectNamespace runs the interactive selector over the namespaces of a context
+// It returns "" when the context has no namespaces to offer.
+func selectNamespace(m *kubeconfig.Manager, contextName, currentNamespace string) (string, error) {
+	if err := requireInput("A namespace name is required"); err != nil {
+		return "", err
+	}
+
+	// Get available namespaces
+	namespaces := getNamespaces(m, contextName)
+
+	// Check if we have namespaces to display
+	if len(namespaces) == 0 {
+		ui.PrintWarning("No namespaces available for context", contextName)
+		return "", nil
+	}
+
+	// Sort namespaces and prioritize the current namespace
+	namespaces = ui.SortNamespaces(namespaces, currentNamespace, settings.Selector.CurrentFirst)
+
+	// Create an interactive selector, on the last used namespace if the kubeconfig lost it
+	selector := ui.CreateNamespaceSelectorAt(namespaces, currentNamespace, contextName, selectorCursor(m, contextName, currentNamespace))
+	selection, err := ui.RunSelector(selector)
+	if err != nil {
+		return "", newCommandError("Namespace selection failed", err)
+	}
+	return selection, nil
+}
 
+// switchNamespace changes the namespace of the current context to one given
+// by name, warning when it doesn't exist in the cluster or creating it with
+// --create-namespace
+func switchNamespace(cmd *cobra.Command, m *kubeconfig.Manager, currentContext, currentNamespace, namespace string) error {
 	// If the specified namespace is the same as the current one, don't do anything
 	if namespace == currentNamespace {
 		ui.PrintWarning(fmt.Sprintf("Namespace '%s' is already selected", namespace))
-		return
+		return nil
 	}
 
-	// Verify that the specified namespace exists for this context
-	namespaces, err := kubeconfig.GetNamespaces()
-	if err != nil {
-		ui.PrintError("Error retrieving namespaces", err, true)
+	if err := requireForce(cmd, []string{currentContext}, "change the namespace of protected context"); err != nil {
+		return err
 	}
 
-	// Check if the specified namespace exists
-	namespaceExists := false
-	for _, ns := range namespaces {
-		if ns == namespace {
-			namespaceExists = true
-			break
+	return setNamespace(cmd, m, currentContext, currentNamespace, namespace, true)
+}
+
+// ensureNamespace checks that a namespace given by name exists in a context's
+// cluster. A missing namespace is created with --create-namespace; otherwise
+// kontext warns and continues, since the user explicitly asked for it.
+func ensureNamespace(cmd *cobra.Command, m *kubeconfig.Manager, contextName, namespace string) error {
+	if slices.Contains(getNamespaces(m, contextName), namespace) {
+		return nil
+	}
+	if create, _ := cmd.Flags().GetBool("create-namespace"); create {
+		return createNamespace(m, contextName, namespace)
+	}
+	ui.PrintWarning(fmt.Sprintf("Namespace '%s' does not exist in context '%s'", namespace, contextName))
+	return nil
+}
+
+// selectorCursor returns the namespace the selector starts on: the current
+// one, or the one last chosen in the context when the kubeconfig has none
+func selectorCursor(m *kubeconfig.Manager, contextName, currentNamespace string) string {
+	if namespaceUnset(m, contextName) {
+		if last := lastNamespace(contextName); last != "" {
+			return last
 		}
 	}
+	return currentNamespace
+}
 
-	if !namespaceExists {
-		ui.PrintWarning(fmt.Sprintf("Namespace '%s' does not exist in context '%s'", namespace, currentContext))
-		// Continue anyway since the user explicitly requested this namespace
+// namespaceUnset reports whether the kubeconfig has no namespace for a context
+// kontext then shows "default", but the namespace may have been wiped by another tool.
+func namespaceUnset(m *kubeconfig.Manager, contextName string) bool {
+	config, err := m.Config()
+	if err != nil {
+		return false
 	}
+	context, exists := config.Contexts[contextName]
+	return exists && context.Namespace == ""
+}
 
-	// Change the namespace
-	err = kubeconfig.SetNamespace(namespace)
+// createNamespace creates a namespace in a context's cluster with the labels
+// from namespaces.labels, for --create-namespace
+// Callers guard protected contexts first: switchNamespace requires --force and
+// applySwitch confirms the switch.
+func createNamespace(m *kubeconfig.Manager, contextName, namespace string) error {
+	if problems := validation.IsDNS1123Label(namespace); len(problems) > 0 {
+		return fmt.Errorf("invalid namespace name '%s': %s", namespace, strings.Join(problems, "; "))
+	}
+
+	err := m.CreateNamespaceForContext(contextName, namespace, settings.Namespaces.Labels)
+	if errors.Is(err, kubeconfig.ErrForbidden) {
+		return &commandError{
+			Msg: fmt.Sprintf("Not allowed to create namespace '%s' in context '%s'", namespace, contextName),
+			Err: err,
+			Details: func() {
+				ui.PrintNote("Check your permissions with", fmt.Sprintf("kubectl auth can-i create namespaces --context %s", contextName))
+			},
+		}
+	}
 	if err != nil {
-		ui.PrintError("Error setting namespace", err, true)
+		return newCommandError(fmt.Sprintf("Error creating namespace '%s'", namespace), err)
+	}
+
+	ui.PrintSuccess("Created namespace", namespace, fmt.Sprintf("in context %s", contextName))
+	return nil
+}
+
+// setNamespace changes the namespace of the current context, running the switch hooks around it
+// With ensure, the namespace is checked or created after the pre hooks, which
+// may veto the change or refresh the credentials.
+func setNamespace(cmd *cobra.Command, m *kubeconfig.Manager, currentContext, currentNamespace, namespace string, ensure bool) error {
+	change := hooks.Change{
+		Kind:         "namespace",
+		OldContext:   currentContext,
+		OldNamespace: currentNamespace,
+		NewContext:   currentContext,
+		NewNamespace: namespace,
+	}
+	if err := runPreHooks(change); err != nil {
+		return err
+	}
+	if ensure {
+		if err := ensureNamespace(cmd, m, currentContext, namespace); err != nil {
+			return err
+		}
+	}
+
+	if err := m.SetNamespace(namespace); err != nil {
+		return newCommandError("Error setting namespace", err)
 	}
 
 	ui.PrintSuccess("Switched to namespace", namespace, fmt.Sprintf("in context %s", currentContext))
+	recordSwitch(m, currentContext, namespace, currentContext, currentNamespace)
+	recordNamespace(currentContext, namespace)
+	auditChange(m, change, "")
+	runPostHooks(change)
+	return nil
 }
 
 func init() {
@@ -141,4 +257,7 @@ func init() {
 
 	// Add flags
 	nsCmd.Flags().BoolP("show", "s", false, "Only show the current namespace without the selector")
+	nsCmd.Flags().Bool("create-namespace", false, "Create the namespace if it doesn't exist, with the labels from namespaces.labels in the config")
+	addForceFlag(nsCmd, "Change the namespace of a protected context")
+	addOutputFlag(nsCmd)
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// recordingClusterClient is a ClusterClient with the default namespace only,
+// recording the namespaces it is asked to create
+type recordingClusterClient struct {
+	created []string
+}
+
+func (c *recordingClusterClient) ListNamespaces(config *api.Config, contextName string) ([]string, error) {
+	return []string{"default"}, nil
+}
+
+func (c *recordingClusterClient) Probe(config *api.Config, contextName string, timeout time.Duration) error {
+	return nil
+}
+
+func (c *recordingClusterClient) CreateNamespace(config *api.Config, contextName, namespace string, labels map[string]string) error {
+	c.created = append(c.created, namespace)
+	return nil
+}
+
+func TestPreHookVetoSkipsCreateNamespace(t *testing.T) {
+	t.Setenv(history.StateDirEnv, t.TempDir())
+	s := config.Default()
+	s.Hooks.Pre = []config.Hook{{Run: "exit 1"}}
+	useSettings(t, s)
+
+	tests := []struct {
+		name   string
+		change func(cmd *cobra.Command, m *kubeconfig.Manager) error
+	}{
+		{
+			name: "namespace",
+			change: func(cmd *cobra.Command, m *kubeconfig.Manager) error {
+				return switchNamespace(cmd, m, "dev", "default", "payments")
+			},
+		},
+		{
+			name: "context",
+			change: func(cmd *cobra.Command, m *kubeconfig.Manager) error {
+				return applySwitch(cmd, m, "prod", "payments")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("create-namespace", true, "")
+			addForceFlag(cmd, "")
+
+			client := &recordingClusterClient{}
+			saved := false
+			m := kubeconfig.NewManager(
+				kubeconfig.WithConfig(showTestConfig()),
+				kubeconfig.WithClusterClient(client),
+				kubeconfig.WithWriter(kubeconfig.WriterFunc(func(*api.Config) error {
+					saved = true
+					return nil
+				})),
+			)
+
+			if err := tt.change(cmd, m); err == nil {
+				t.Fatal("change error = nil, want the pre hook veto")
+			}
+			if len(client.created) > 0 {
+				t.Errorf("CreateNamespace() called for %v after a vetoed change", client.created)
+			}
+			if saved {
+				t.Error("kubeconfig saved after a vetoed change")
+			}
+		})
+	}
+}
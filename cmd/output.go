@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
+	"github.com/user-cube/kontext/pkg/ui"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// addOutputFlag registers the shared -o/--output flag on a command
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "", "Output format: json, yaml, name, wide or go-template=...")
+}
+
+// getOutputFormat returns the output format selected with -o
+// An invalid format is a usage error.
+func getOutputFormat(cmd *cobra.Command) (output.Format, error) {
+	value, _ := cmd.Flags().GetString("output")
+	return output.ParseFormat(value)
+}
+
+// printObject prints a document in a structured output format
+func printObject(format output.Format, obj output.Object) error {
+	if err := output.Print(ui.Default().Out, format, obj); err != nil {
+		return newCommandError("Error printing output", err)
+	}
+	return nil
+}
+
+// contextInfos summarizes every context of the config, sorted by name
+func contextInfos(config *api.Config) []output.ContextInfo {
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infos := make([]output.ContextInfo, 0, len(names))
+	for _, name := range names {
+		details, err := kubeconfig.DescribeContext(config, name, false)
+		if err != nil {
+			continue
+		}
+		info := output.NewContextInfo(details)
+		info.Tags = settings.TagsFor(name)
+		infos = append(infos, info)
+	}
+	return infos
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/pin"
+	"github.com/user-cube/kontext/pkg/shell"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// Environment variables the shell integration uses to track the active pin
+const (
+	// pinEnv is the .kontext file in effect
+	pinEnv = "KONTEXT_PIN"
+	// pinKubeconfigEnv is the KUBECONFIG to restore when leaving the directory
+	pinKubeconfigEnv = "KONTEXT_PIN_KUBECONFIG"
+	// pinReportedEnv is the .kontext file whose problem was already reported
+	pinReportedEnv = "KONTEXT_PIN_REPORTED"
+)
+
+// setKubeconfigEnv points this process at a kubeconfig, "" meaning the default one
+func setKubeconfigEnv(path string) {
+	if path == "" {
+		_ = os.Unsetenv("KUBECONFIG")
+	} else {
+		_ = os.Setenv("KUBECONFIG", path)
+	}
+}
+
+// applyPin writes the session kubeconfig of an allowed .kontext file and returns its path
+// original is the KUBECONFIG the session is copied from. The session is only
+// rewritten when it is older than the .kontext file or the kubeconfig, so
+// namespace changes made inside the directory last until the user leaves it.
+// Pins are refused in fragment mode, where kontext reads the fragments and
+// not KUBECONFIG, so kontext and kubectl would disagree on the context.
+func applyPin(path, original, session string) (string, *pin.Pin, error) {
+	if kubeconfig.IsFragmentMode() {
+		return "", nil, fmt.Errorf("%w (kubeconfig.glob is set)", pin.ErrFragmentMode)
+	}
+	if err := pin.CheckAllowed(path); err != nil {
+		return "", nil, err
+	}
+	pinned, err := pin.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sessionPath := pin.SessionPath(path, session)
+	setKubeconfigEnv(original)
+	if !isNewer(sessionPath, path, kubeconfig.GetKubeConfigPath()) {
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return "", nil, err
+		}
+		contextName := resolveContextArg(kubeConfig, pinned.Context)
+		if err := pin.WriteSession(kubeConfig, contextName, pinned.Namespace, sessionPath); err != nil {
+			return "", nil, err
+		}
+	}
+	return sessionPath, pinned, nil
+}
+
+// isNewer reports whether path exists and was modified after every file in others
+// Files in others that don't exist are ignored.
+func isNewer(path string, others ...string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	for _, other := range others {
+		if otherInfo, err := os.Stat(other); err == nil && !info.ModTime().After(otherInfo.ModTime()) {
+			return false
+		}
+	}
+	return true
+}
+
+// hookCmd represents the hook command
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Print the shell statements applying .kontext files and context variables",
+	Long: `Print the shell statements the shell integration evaluates before every
+prompt (see 'kontext init').
+
+They apply the nearest allowed .kontext file above the working directory by
+pointing KUBECONFIG at a session kubeconfig holding only the pinned context,
+its cluster and user, restore KUBECONFIG when you leave the directory, and
+export the environment variables of the effective context (see 'kontext env').
+Messages go to stderr so only statements are evaluated.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shellName, err := getShell(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Statements go to stdout, everything else to stderr
+		out := ui.Default().Out
+		ui.Default().Out = ui.Default().Err
+		defer func() { ui.Default().Out = out }()
+
+		var lines []string
+		session := os.Getenv(shell.SessionEnv)
+		active := os.Getenv(pinEnv)
+		original := os.Getenv("KUBECONFIG")
+		if active != "" {
+			original = os.Getenv(pinKubeconfigEnv)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return newCommandError("Error reading the working directory", err)
+		}
+		path, err := pin.Find(cwd)
+		if err != nil {
+			ui.Debugf("looking for %s failed: %v", pin.FileName, err)
+		}
+
+		var sessionPath string
+		if path != "" {
+			var pinned *pin.Pin
+			sessionPath, pinned, err = applyPin(path, original, session)
+			switch {
+			case err != nil && os.Getenv(pinReportedEnv) != path:
+				if errors.Is(err, pin.ErrNotAllowed) {
+					ui.PrintWarning(err.Error(), "(run 'kontext allow' to use it)")
+				} else {
+					ui.PrintWarning(fmt.Sprintf("Ignoring %s", path), err.Error())
+				}
+				lines = append(lines, shell.Export(shellName, pinReportedEnv, path))
+			case err == nil && active != path:
+				ui.PrintNote(fmt.Sprintf("Using context %s from %s", pinned.Context, path))
+			}
+		}
+		if path == "" && os.Getenv(pinReportedEnv) != "" {
+			lines = append(lines, shell.Unset(shellName, pinReportedEnv))
+		}
+
+		switch {
+		case sessionPath != "":
+			if active == "" {
+				lines = append(lines, shell.Export(shellName, pinKubeconfigEnv, original))
+			}
+			if active != path || os.Getenv("KUBECONFIG") != sessionPath {
+				lines = append(lines,
+					shell.Export(shellName, "KUBECONFIG", sessionPath),
+					shell.Export(shellName, pinEnv, path))
+			}
+			setKubeconfigEnv(sessionPath)
+		case active != "":
+			if original == "" {
+				lines = append(lines, shell.Unset(shellName, "KUBECONFIG"))
+			} else {
+				lines = append(lines, shell.Export(shellName, "KUBECONFIG", original))
+			}
+			lines = append(lines, shell.Unset(shellName, pinEnv), shell.Unset(shellName, pinKubeconfigEnv))
+			ui.PrintNote(fmt.Sprintf("Left %s, back to your kubeconfig", filepath.Dir(active)))
+			setKubeconfigEnv(original)
+		}
+
+		// Sessions hold credentials, so they don't outlive the pin
+		if active != "" && (active != path || sessionPath == "") {
+			if err := pin.RemoveSession(active, session); err != nil {
+				ui.Debugf("removing the session of %s failed: %v", active, err)
+			}
+		}
+
+		// The environment follows the effective context; a broken kubeconfig only unsets it
+		vars := map[string]string{}
+		if kubeConfig, err := newManager().Config(); err != nil {
+			ui.Debugf("loading the kubeconfig failed: %v", err)
+		} else if kubeConfig.CurrentContext != "" {
+			vars = settings.EnvFor(kubeConfig.CurrentContext)
+		}
+
+		script := ""
+		if len(lines) > 0 {
+			script = strings.Join(lines, "\n") + "\n"
+		}
+		script += shell.EnvScript(shellName, os.Getenv(shell.ManagedEnv), vars)
+		fmt.Fprint(out, script)
+		return nil
+	},
+}
+
+// pinPathArg returns the .kontext file named by args or the nearest one above the working directory
+func pinPathArg(args []string) (string, error) {
+	if len(args) > 0 {
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, pin.FileName)
+		}
+		return path, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	path, err := pin.Find(cwd)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", fmt.Errorf("no %s file found in %s or its parents", pin.FileName, cwd)
+	}
+	return path, nil
+}
+
+// allowCmd represents the allow command
+var allowCmd = &cobra.Command{
+	Use:   "allow [path]",
+	Short: "Trust a .kontext file so the shell integration applies it",
+	Long: `Trust a .kontext file (the nearest one above the working directory by
+default). Only the file's current content is trusted: after any change it must
+be allowed again.
+
+A .kontext file pins a directory to a context and namespace:
+
+  context: prod          # context name or alias
+  namespace: payments    # optional`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := pinPathArg(args)
+		if err != nil {
+			return newCommandError("Error finding .kontext file", err)
+		}
+		pinned, err := pin.Load(path)
+		if err != nil {
+			return newCommandError("Error reading .kontext file", err)
+		}
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+		if _, exists := kubeConfig.Contexts[resolveContextArg(kubeConfig, pinned.Context)]; !exists {
+			ui.PrintWarning(fmt.Sprintf("Context '%s' does not exist in your kubeconfig", pinned.Context))
+		}
+		if settings.IsProtected(resolveContextArg(kubeConfig, pinned.Context)) {
+			ui.PrintWarning(fmt.Sprintf("Context '%s' is protected", pinned.Context))
+		}
+
+		if err := pin.Allow(path); err != nil {
+			return newCommandError("Error allowing .kontext file", err)
+		}
+		details := "context " + pinned.Context
+		if pinned.Namespace != "" {
+			details += ", namespace " + pinned.Namespace
+		}
+		ui.PrintSuccess(fmt.Sprintf("Allowed %s", path), fmt.Sprintf("(%s)", details))
+		return nil
+	},
+}
+
+// denyCmd represents the deny command
+var denyCmd = &cobra.Command{
+	Use:   "deny [path]",
+	Short: "Stop trusting a .kontext file",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := pinPathArg(args)
+		if err != nil {
+			return newCommandError("Error finding .kontext file", err)
+		}
+		if err := pin.Deny(path); err != nil {
+			return newCommandError("Error denying .kontext file", err)
+		}
+		ui.PrintSuccess("Denied", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd, allowCmd, denyCmd)
+
+	// Add flags
+	addShellFlag(hookCmd)
+}
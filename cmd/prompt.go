@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the current context and namespace for a shell prompt",
+	Long: `Print the current context and namespace as a short segment for your shell
+prompt. Protected contexts are shown in the warning color with a badge.
+
+With --shell the color codes are marked as zero-width for bash or zsh, and
+colors are enabled even though the output isn't a terminal (unless color is
+set to never or NO_COLOR is set). Nothing is printed when no context is
+selected, so the segment disappears.
+
+Examples:
+  # bash (~/.bashrc)
+  PS1='$(kontext prompt --shell bash) \$ '
+
+  # zsh (~/.zshrc)
+  setopt PROMPT_SUBST
+  PROMPT='$(kontext prompt --shell zsh) %# '`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell, _ := cmd.Flags().GetString("shell")
+		switch shell {
+		case "", "bash", "zsh":
+		default:
+			return fmt.Errorf("invalid shell '%s', use bash or zsh", shell)
+		}
+
+		if shell != "" && !cmd.Flags().Changed("color") && settings.Color == "auto" && os.Getenv("NO_COLOR") == "" {
+			ui.SetColor(true)
+		}
+
+		m := newManager()
+		currentContext, err := m.GetCurrentContext()
+		if err != nil {
+			return newCommandError("Error retrieving current context", err)
+		}
+		if currentContext == "" {
+			return nil
+		}
+		namespace, _ := m.GetCurrentNamespace()
+
+		segment := ui.Default().Segment(currentContext, namespace)
+		fmt.Fprintln(ui.Default().Out, ui.WrapEscapes(segment, shell))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+
+	// Add flags
+	promptCmd.Flags().String("shell", "", "Mark color codes as zero-width for this shell: bash or zsh")
+}
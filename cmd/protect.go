@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// confirmedContexts are the protected contexts confirmed during this run
+// Confirming a switch also allows changing the context's namespace afterwards.
+var confirmedContexts = make(map[string]bool)
+
+// addForceFlag registers the --force flag needed to change protected contexts
+func addForceFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().Bool("force", false, usage)
+}
+
+// forced reports whether --force was passed to cmd
+func forced(cmd *cobra.Command) bool {
+	force, _ := cmd.Flags().GetBool("force")
+	return force
+}
+
+// confirmProtectedSwitch asks to confirm a switch to a protected context
+// Depending on protected.confirm the user types the context name or answers
+// yes. --force skips the prompt, which is required when input is disabled.
+func confirmProtectedSwitch(cmd *cobra.Command, contextName, currentContext string) error {
+	if !settings.IsProtected(contextName) || confirmedContexts[contextName] {
+		return nil
+	}
+	if forced(cmd) {
+		confirmedContexts[contextName] = true
+		return nil
+	}
+	if contextName == currentContext {
+		return nil
+	}
+
+	if err := requireInput(fmt.Sprintf("Context '%s' is protected, pass --force to switch to it", contextName)); err != nil {
+		return err
+	}
+
+	ui.PrintWarning(fmt.Sprintf("Context '%s' is protected", contextName))
+	var confirmed bool
+	var err error
+	if settings.Protected.Confirm == "yes" {
+		confirmed, err = ui.ConfirmAction(fmt.Sprintf("Switch to protected context '%s'", contextName))
+	} else {
+		confirmed, err = ui.ConfirmTyped("Type the context name to switch to it", contextName)
+	}
+	if err != nil {
+		return newCommandError("Error during confirmation", err)
+	}
+	if !confirmed {
+		return newCommandError("Switch canceled", fmt.Errorf("%w: '%s' was not confirmed", ui.ErrSelectionCanceled, contextName))
+	}
+
+	confirmedContexts[contextName] = true
+	return nil
+}
+
+// requireForce fails when changing a protected context without --force
+// action describes the change, for example "change the namespace of".
+func requireForce(cmd *cobra.Command, contextNames []string, action string) error {
+	if forced(cmd) {
+		return nil
+	}
+	for _, name := range contextNames {
+		if settings.IsProtected(name) && !confirmedContexts[name] {
+			return newCommandError(fmt.Sprintf("Refusing to %s '%s'", action, name),
+				fmt.Errorf("%w, pass --force to continue", config.ErrProtectedContext))
+		}
+	}
+	return nil
+}
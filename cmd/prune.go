@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/audit"
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// pruneCandidate is a kubeconfig entry that kontext prune proposes to remove
+type pruneCandidate struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// Label returns the text shown for the candidate in the selector
+func (c pruneCandidate) Label() string {
+	return fmt.Sprintf("%s %s (%s)", c.Kind, c.Name, c.Reason)
+}
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove orphan and stale entries from your kubeconfig",
+	Long: `Find and remove kubeconfig entries that are no longer useful:
+
+  - clusters and users not referenced by any context
+  - contexts whose cluster has been unreachable for N days (--unreachable-days)
+  - contexts not switched to in N days (--unused-days)
+
+Reachability comes from the probe history kontext records whenever it talks to
+a cluster; use --probe to check every context now. Usage comes from the switch
+history kontext records on every switch.
+
+The candidates are shown in an interactive checklist and only the selected
+entries are removed. Protected contexts are never proposed.
+
+Examples:
+  # Remove orphan clusters and users
+  kontext prune
+
+  # Also propose contexts unused for 60 days or unreachable for 14 days
+  kontext prune --unused-days 60 --unreachable-days 14
+
+  # Probe every cluster first, then show what would be removed
+  kontext prune --probe --unreachable-days 7 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unusedDays, _ := cmd.Flags().GetInt("unused-days")
+		unreachableDays, _ := cmd.Flags().GetInt("unreachable-days")
+		probe, _ := cmd.Flags().GetBool("probe")
+		probeTimeout := settings.Timeouts.Probe.Duration()
+		if cmd.Flags().Changed("probe-timeout") {
+			probeTimeout, _ = cmd.Flags().GetDuration("probe-timeout")
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		m := newManager()
+		config, err := m.Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+
+		contextNames := make([]string, 0, len(config.Contexts))
+		for name := range config.Contexts {
+			contextNames = append(contextNames, name)
+		}
+		sort.Strings(contextNames)
+
+		// A tag filter limits which contexts are probed and proposed
+		if contextNames, err = filterByTags(cmd, contextNames); err != nil {
+			return err
+		}
+
+		if probe {
+			probeContexts(m, contextNames, probeTimeout)
+		}
+
+		// Protected contexts are never proposed, delete them explicitly instead
+		unprotected := contextNames[:0:0]
+		for _, name := range contextNames {
+			if !settings.IsProtected(name) {
+				unprotected = append(unprotected, name)
+			}
+		}
+
+		candidates, err := findPruneCandidates(unprotected, unusedDays, unreachableDays, m.Now())
+		if err != nil {
+			return newCommandError("Error reading history", err)
+		}
+		for _, name := range kubeconfig.OrphanClusters(config) {
+			candidates = append(candidates, pruneCandidate{Kind: "cluster", Name: name, Reason: "not referenced by any context"})
+		}
+		for _, name := range kubeconfig.OrphanAuthInfos(config) {
+			candidates = append(candidates, pruneCandidate{Kind: "user", Name: name, Reason: "not referenced by any context"})
+		}
+
+		if len(candidates) == 0 {
+			ui.PrintSuccess("Nothing to prune")
+			return nil
+		}
+
+		labels := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			labels[i] = candidate.Label()
+		}
+
+		if dryRun {
+			ui.PrintList("Entries that would be pruned:", labels)
+			return nil
+		}
+
+		selected := candidates
+		if !yes {
+			if err := requireInput("Selection required, pass --yes to prune every candidate or --dry-run to list them"); err != nil {
+				return err
+			}
+
+			indices, err := ui.MultiSelect("Select entries to prune:", labels, true)
+			if err != nil {
+				return newCommandError("Selection failed", err)
+			}
+
+			selected = make([]pruneCandidate, 0, len(indices))
+			for _, i := range indices {
+				selected = append(selected, candidates[i])
+			}
+		}
+
+		if len(selected) == 0 {
+			ui.PrintWarning("Nothing selected, kubeconfig left unchanged")
+			return nil
+		}
+
+		var contexts, clusters, users []string
+		for _, candidate := range selected {
+			switch candidate.Kind {
+			case "context":
+				contexts = append(contexts, candidate.Name)
+			case "cluster":
+				clusters = append(clusters, candidate.Name)
+			case "user":
+				users = append(users, candidate.Name)
+			}
+		}
+
+		if err := m.RemoveEntries(contexts, clusters, users); err != nil {
+			return newCommandError("Error pruning kubeconfig", err)
+		}
+		auditRemoval(m, audit.OpPrune, contexts, clusters, users)
+
+		for _, candidate := range selected {
+			ui.PrintSuccess(fmt.Sprintf("Pruned %s", candidate.Kind), candidate.Name)
+		}
+		return nil
+	},
+}
+
+// findPruneCandidates returns the contexts that are stale according to the
+// switch and probe history as of now. A threshold of zero days disables that check.
+func findPruneCandidates(contextNames []string, unusedDays, unreachableDays int, now time.Time) ([]pruneCandidate, error) {
+	reasons := make(map[string]string)
+
+	if unreachableDays > 0 {
+		probes, err := history.LoadProbes()
+		if err != nil {
+			return nil, err
+		}
+		for name, since := range history.Unreachable(contextNames, probes, now.AddDate(0, 0, -unreachableDays)) {
+			reasons[name] = fmt.Sprintf("unreachable for %d days", daysSince(since, now))
+		}
+	}
+
+	if unusedDays > 0 {
+		events, err := history.LoadSwitches()
+		if err != nil {
+			return nil, err
+		}
+		for name, last := range history.Unused(contextNames, events, now.AddDate(0, 0, -unusedDays)) {
+			if _, exists := reasons[name]; exists {
+				continue
+			}
+			if last.IsZero() {
+				reasons[name] = "never used"
+			} else {
+				reasons[name] = fmt.Sprintf("not used for %d days", daysSince(last, now))
+			}
+		}
+	}
+
+	candidates := make([]pruneCandidate, 0, len(reasons))
+	for _, name := range contextNames {
+		if reason, exists := reasons[name]; exists {
+			candidates = append(candidates, pruneCandidate{Kind: "context", Name: name, Reason: reason})
+		}
+	}
+	return candidates, nil
+}
+
+// probeContexts checks every context's cluster concurrently and records the results
+// The manager's kubeconfig must already be loaded: the probes only read it.
+func probeContexts(m *kubeconfig.Manager, contextNames []string, timeout time.Duration) {
+	ui.PrintNote(fmt.Sprintf("Probing %d contexts", len(contextNames)), fmt.Sprintf("(timeout %s)", timeout))
+
+	results := make([]error, len(contextNames))
+	var wg sync.WaitGroup
+	for i, name := range contextNames {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = m.ProbeContext(name, timeout)
+		}(i, name)
+	}
+	wg.Wait()
+
+	// Record sequentially since the probe history is a single file
+	for i, name := range contextNames {
+		if results[i] == nil || errors.Is(results[i], kubeconfig.ErrClusterUnreachable) {
+			recordProbe(m, name, results[i])
+		}
+	}
+}
+
+// daysSince returns the number of whole days between t and now
+func daysSince(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	// Add flags
+	pruneCmd.Flags().Int("unused-days", 0, "Propose contexts not used in this many days (0 disables)")
+	pruneCmd.Flags().Int("unreachable-days", 0, "Propose contexts whose cluster has been unreachable for this many days (0 disables)")
+	pruneCmd.Flags().Bool("probe", false, "Probe every context's cluster before looking for unreachable ones")
+	pruneCmd.Flags().Duration("probe-timeout", 5*time.Second, "Timeout for each cluster probe (default from timeouts.probe in the config)")
+	pruneCmd.Flags().Bool("dry-run", false, "Only show what would be pruned")
+	pruneCmd.Flags().BoolP("yes", "y", false, "Prune all candidates without the interactive checklist")
+	addTagFlag(pruneCmd)
+}
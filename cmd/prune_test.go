@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// fixedClock is a kubeconfig.Clock that always returns the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestFindPruneCandidatesWithClock(t *testing.T) {
+	t.Setenv(history.StateDirEnv, t.TempDir())
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	then := kubeconfig.NewManager(kubeconfig.WithConfig(api.NewConfig()), kubeconfig.WithClock(fixedClock(start)))
+	recordSwitch(then, "dev", "default", "", "")
+	later := kubeconfig.NewManager(kubeconfig.WithConfig(api.NewConfig()), kubeconfig.WithClock(fixedClock(start.AddDate(0, 0, 10))))
+	recordSwitch(later, "prod", "default", "dev", "default")
+
+	now := start.AddDate(0, 0, 40)
+	candidates, err := findPruneCandidates([]string{"dev", "prod"}, 35, 0, now)
+	if err != nil {
+		t.Fatalf("findPruneCandidates() error = %v", err)
+	}
+	want := []pruneCandidate{{Kind: "context", Name: "dev", Reason: "not used for 40 days"}}
+	if !reflect.DeepEqual(candidates, want) {
+		t.Errorf("findPruneCandidates() = %+v, want %+v", candidates, want)
+	}
+}
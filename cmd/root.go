@@ -4,13 +4,9 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/user-cube/kontext/pkg/kubeconfig"
-	"github.com/user-cube/kontext/pkg/ui"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,97 +24,28 @@ Examples:
   kontext my-context                # Switch to a specific context
   kontext -n                        # Switch context and then select namespace
   kontext my-context -n             # Switch to context and then select namespace
-  kontext my-context -n my-namespace # Switch to context and set namespace directly`,
+  kontext my-context -n my-namespace # Switch to context and set namespace directly
+  kontext prod --for 30m            # Switch to prod, and back after 30 minutes
+  kontext dev-us --keep-namespace   # Switch context, staying in the same namespace
+  kontext dev -n feature --create-namespace # Switch, creating the namespace if needed`,
+	// Arguments that aren't subcommands are context names or aliases
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: contextCompletion,
 	// When no subcommands are provided, run the switch command functionality
-	Run: func(cmd *cobra.Command, args []string) {
-		// Get the list of non-flag arguments (context and possibly namespace)
-		nonFlagArgs := []string{}
-		for _, arg := range args {
-			// Check if this arg looks like a flag
-			if !strings.HasPrefix(arg, "-") {
-				nonFlagArgs = append(nonFlagArgs, arg)
-			}
-		}
-
-		// Check if a namespace is specified after the -n flag
-		setNS, _ := cmd.Flags().GetBool("set-namespace")
-		var namespaceArg string
-
-		// Get all args that might contain the -n flag and a namespace argument
-		allArgs := os.Args
-		if setNS {
-			// Look for a namespace argument after the -n flag
-			for i, arg := range allArgs {
-				if (arg == "-n" || arg == "--set-namespace") && i+1 < len(allArgs) && !strings.HasPrefix(allArgs[i+1], "-") {
-					// Check if the next arg isn't another flag and isn't part of the context name
-					if len(nonFlagArgs) == 0 || allArgs[i+1] != nonFlagArgs[0] {
-						namespaceArg = allArgs[i+1]
-						break
-					}
-				}
-			}
-		}
-
-		// If we found a namespace arg, run with it; otherwise use the normal runSwitch
-		if namespaceArg != "" {
-			// If we have a context, switch to it first, then set the namespace directly
-			if len(nonFlagArgs) > 0 {
-				// Create args with just the context
-				contextArgs := []string{nonFlagArgs[0]}
-				// Call runSwitch with just the context
-				runSwitch(cmd, contextArgs)
-
-				// Before setting the namespace, check if it exists
-				newContext, err := kubeconfig.GetCurrentContext()
-				if err != nil {
-					ui.PrintError("Error retrieving current context", err, true)
-				}
-
-				namespaces, err := kubeconfig.GetNamespaces()
-				if err != nil {
-					ui.PrintError("Error retrieving namespaces", err, true)
-				}
-
-				// Check if the specified namespace exists
-				namespaceExists := false
-				for _, ns := range namespaces {
-					if ns == namespaceArg {
-						namespaceExists = true
-						break
-					}
-				}
-
-				if !namespaceExists {
-					ui.PrintWarning(fmt.Sprintf("Namespace '%s' does not exist in context '%s'", namespaceArg, newContext))
-					// Continue anyway since the user explicitly requested this namespace
-				}
-
-				// Then set the namespace directly
-				nsCmd.Run(nsCmd, []string{namespaceArg})
-			} else {
-				// If no context specified, show context selector and then set namespace
-				runSwitch(cmd, []string{})
-				nsCmd.Run(nsCmd, []string{namespaceArg})
-			}
-		} else if setNS {
-			// No namespace argument but -n flag was specified
-			// First switch context if needed
-			runSwitch(cmd, nonFlagArgs)
-			// Then run namespace selector
-			nsCmd.Run(nsCmd, []string{})
-		} else {
-			// No namespace argument found, use normal flow
-			runSwitch(cmd, nonFlagArgs)
-		}
-	},
+	RunE: runSwitch,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed to stderr and mapped to the documented exit codes.
 func Execute() {
-	err := rootCmd.Execute()
+	// Detect CI and pipes before parsing flags so usage errors are plain too
+	configureInput(false)
+
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
-		os.Exit(1)
+		printCommandError(cmd, err)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -126,6 +53,26 @@ func init() {
 	// Enable shell completion
 	rootCmd.CompletionOptions.DisableDefaultCmd = false
 
+	// Errors are printed by Execute, without the usage text
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	// Global flags
+	rootCmd.PersistentPreRunE = persistentPreRunE
+	rootCmd.PersistentFlags().Bool("no-input", false, "Never prompt; fail when a selection or confirmation would be required (also KONTEXT_NO_INPUT)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only print errors and the requested output")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Print debug details such as the kubeconfig files loaded and API latency")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.PersistentFlags().String("color", "auto", "When to color output: auto, always or never (auto honors NO_COLOR; default from color in the config)")
+	rootCmd.PersistentFlags().Bool("ascii", false, "Use plain ASCII symbols instead of unicode (also KONTEXT_ASCII or ascii in the config)")
+
 	// Add flags - same as switch command
 	rootCmd.Flags().BoolP("set-namespace", "n", false, "Also set the namespace after switching context")
+	rootCmd.Flags().Bool("keep-namespace", false, "Stay in the current namespace if it exists in the new context (default from namespaces.keep in the config)")
+	rootCmd.Flags().Bool("restore-namespace", false, "Switch to the namespace last chosen in the new context (default from namespaces.restore in the config)")
+	rootCmd.Flags().Bool("create-namespace", false, "Create the namespace given with -n if it doesn't exist (labels from namespaces.labels in the config)")
+	addForceFlag(rootCmd, "Switch to a protected context without confirmation")
+	addForFlag(rootCmd)
+	addTagFlag(rootCmd)
+	addGroupByFlag(rootCmd)
 }
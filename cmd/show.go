@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
+	"github.com/user-cube/kontext/pkg/ui"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:     "show [context]",
+	Aliases: []string{"describe"},
+	Short:   "Show everything about a Kubernetes context",
+	Long: `Show detailed information about a Kubernetes context: its cluster (server,
+CA, TLS settings, proxy), its user and authentication method, its default
+namespace, the file it was loaded from and what kontext knows about it: its
+aliases, tags and when it was last used.
+
+Secrets such as tokens and passwords are redacted unless --show-secrets is
+given. If no context is provided, the current context is shown.
+
+Examples:
+  # Show the current context
+  kontext show
+
+  # Show a specific context
+  kontext show my-context
+  kontext describe my-context
+
+  # Show a context by its alias
+  kontext show p
+
+  # Machine-readable output
+  kontext show my-context -o json
+  kontext show my-context -o yaml
+  kontext show -o go-template='{{.cluster.server}}'`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: contextCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		config, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+
+		contextName := config.CurrentContext
+		if len(args) > 0 {
+			contextName = resolveContextArg(config, args[0])
+		}
+		if contextName == "" {
+			return newCommandError("Error retrieving current context", kubeconfig.ErrNoCurrentContext)
+		}
+
+		details, err := contextDetails(config, contextName, showSecrets)
+		if err != nil {
+			return newCommandError("Error describing context", err)
+		}
+
+		if !format.IsText() {
+			return printObject(format, details)
+		}
+
+		printContextDetails(details)
+		return nil
+	},
+}
+
+// contextDetails describes a context of the kubeconfig with kontext's metadata:
+// its aliases, tags and when it was last used
+func contextDetails(kubeConfig *api.Config, contextName string, showSecrets bool) (output.Details, error) {
+	described, err := kubeconfig.DescribeContext(kubeConfig, contextName, showSecrets)
+	if err != nil {
+		return output.Details{}, err
+	}
+
+	if events, err := history.LoadSwitches(); err == nil {
+		if lastUsed, ok := history.LastUsed(events)[contextName]; ok {
+			described.LastUsed = &lastUsed
+		}
+	}
+	return output.Details{
+		ContextDetails: described,
+		Aliases:        settings.AliasesFor(contextName),
+		Tags:           settings.TagsFor(contextName),
+	}, nil
+}
+
+// printContextDetails prints context details in human-readable form
+func printContextDetails(details output.Details) {
+	var current []string
+	if details.Current {
+		current = append(current, "(current)")
+	}
+	ui.PrintSection("Context", details.Name, current...)
+	ui.PrintField("Namespace", details.Namespace)
+	ui.PrintField("Source", details.Source)
+	ui.PrintField("Aliases", strings.Join(details.Aliases, ", "))
+	ui.PrintField("Tags", config.FormatTags(details.Tags))
+	if details.LastUsed != nil {
+		ui.PrintField("Last used", details.LastUsed.Local().Format("2006-01-02 15:04"))
+	}
+
+	cluster := details.Cluster
+	if cluster.Missing {
+		ui.PrintSection("Cluster", cluster.Name, "(missing)")
+	} else {
+		ui.PrintSection("Cluster", cluster.Name)
+		ui.PrintField("Server", cluster.Server)
+		ui.PrintField("Certificate authority", cluster.CertificateAuthority)
+		if cluster.InsecureSkipTLSVerify {
+			ui.PrintField("TLS verification", "disabled (insecure-skip-tls-verify)")
+		}
+		ui.PrintField("TLS server name", cluster.TLSServerName)
+		ui.PrintField("Proxy URL", cluster.ProxyURL)
+		if cluster.Source != details.Source {
+			ui.PrintField("Source", cluster.Source)
+		}
+	}
+
+	user := details.User
+	if user.Missing {
+		ui.PrintSection("User", user.Name, "(missing)")
+		return
+	}
+	ui.PrintSection("User", user.Name)
+	ui.PrintField("Authentication", user.AuthMethod)
+	ui.PrintField("Token", user.Token)
+	ui.PrintField("Token file", user.TokenFile)
+	ui.PrintField("Client certificate", user.ClientCertificate)
+	ui.PrintField("Client key", user.ClientKey)
+	ui.PrintField("Username", user.Username)
+	ui.PrintField("Password", user.Password)
+	ui.PrintField("Impersonate", user.Impersonate)
+	if user.Exec != nil {
+		ui.PrintField("Exec command", strings.TrimSpace(user.Exec.Command+" "+strings.Join(user.Exec.Args, " ")))
+		ui.PrintField("Exec API version", user.Exec.APIVersion)
+		for _, name := range sortedKeys(user.Exec.Env) {
+			ui.PrintField("Exec env "+name, user.Exec.Env[name])
+		}
+	}
+	if user.AuthProvider != nil {
+		ui.PrintField("Auth provider", user.AuthProvider.Name)
+		for _, key := range sortedKeys(user.AuthProvider.Config) {
+			ui.PrintField("Auth provider "+key, user.AuthProvider.Config[key])
+		}
+	}
+	if user.Source != details.Source {
+		ui.PrintField("Source", user.Source)
+	}
+}
+
+// sortedKeys returns the keys of a string map in alphabetical order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	// Add flags
+	showCmd.Flags().Bool("show-secrets", false, "Show tokens, passwords and other secrets instead of redacting them")
+	addOutputFlag(showCmd)
+}
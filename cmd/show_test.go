@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/history"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// useSettings replaces the user configuration for the duration of a test
+func useSettings(t *testing.T, s *config.Config) {
+	t.Helper()
+	previous := settings
+	settings = s
+	t.Cleanup(func() { settings = previous })
+}
+
+// showTestConfig returns an in-memory kubeconfig with a dev and a prod context
+func showTestConfig() *api.Config {
+	kubeConfig := api.NewConfig()
+	kubeConfig.Clusters["prod"] = &api.Cluster{Server: "https://prod.example.com"}
+	kubeConfig.AuthInfos["prod"] = &api.AuthInfo{Token: "prod-token"}
+	kubeConfig.Contexts["dev"] = &api.Context{Cluster: "prod", AuthInfo: "prod"}
+	kubeConfig.Contexts["prod"] = &api.Context{Cluster: "prod", AuthInfo: "prod", Namespace: "apps"}
+	kubeConfig.CurrentContext = "dev"
+	return kubeConfig
+}
+
+func TestShowByAlias(t *testing.T) {
+	t.Setenv(history.StateDirEnv, t.TempDir())
+	s := config.Default()
+	if err := s.SetAlias("p", "prod"); err != nil {
+		t.Fatalf("SetAlias() error = %v", err)
+	}
+	s.SetTags("prod", map[string]string{"env": "prod"})
+	useSettings(t, s)
+
+	kubeConfig := showTestConfig()
+	contextName := resolveContextArg(kubeConfig, "p")
+	if contextName != "prod" {
+		t.Fatalf("resolveContextArg(p) = %v, want prod", contextName)
+	}
+
+	details, err := contextDetails(kubeConfig, contextName, false)
+	if err != nil {
+		t.Fatalf("contextDetails() error = %v", err)
+	}
+	if details.Name != "prod" || details.Namespace != "apps" || details.Cluster.Server != "https://prod.example.com" {
+		t.Errorf("contextDetails() = %+v, want the prod context", details)
+	}
+	if !reflect.DeepEqual(details.Aliases, []string{"p"}) {
+		t.Errorf("contextDetails() aliases = %v, want [p]", details.Aliases)
+	}
+	if !reflect.DeepEqual(details.Tags, map[string]string{"env": "prod"}) {
+		t.Errorf("contextDetails() tags = %v, want env=prod", details.Tags)
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/audit"
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/output"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show which contexts and namespaces you actually use",
+	Long: `Show how often you switched to each context and namespace, when you last
+used it and how long you spent in it, from the switch history kontext records.
+
+Time spent is the time until the next switch, so the current context counts
+until now. Switches made outside kontext (kubectl config use-context) are not
+in the history. Contexts not used in --unused-days are highlighted as
+candidates for 'kontext prune --unused-days'.
+
+Examples:
+  # Usage of every context
+  kontext stats
+
+  # Only the last month, flagging contexts unused for 60 days
+  kontext stats --since 30d --unused-days 60
+
+  # Per-user report for team-level aggregation
+  kontext stats -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		m := newManager()
+		now := m.Now()
+		var since time.Time
+		if value, _ := cmd.Flags().GetString("since"); value != "" {
+			if since, err = parseTimeFlag(value, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+		}
+		unusedDays, _ := cmd.Flags().GetInt("unused-days")
+		if unusedDays < 0 {
+			return fmt.Errorf("invalid --unused-days %d: can't be negative", unusedDays)
+		}
+
+		config, err := m.Config()
+		if err != nil {
+			return newCommandError("Error retrieving contexts", err)
+		}
+		contextNames := make([]string, 0, len(config.Contexts))
+		for name := range config.Contexts {
+			contextNames = append(contextNames, name)
+		}
+		sort.Strings(contextNames)
+		if contextNames, err = filterByTags(cmd, contextNames); err != nil {
+			return err
+		}
+
+		events, err := history.LoadSwitches()
+		if err != nil {
+			return newCommandError("Error reading history", err)
+		}
+
+		stats := output.Stats{User: audit.CurrentUser(), Since: since, UnusedDays: unusedDays}
+		stats.Host, _ = os.Hostname()
+		stats.Contexts = contextStats(contextNames, events, since, now, unusedDays)
+
+		if !format.IsText() {
+			return printObject(format, stats)
+		}
+		printStats(stats)
+		return nil
+	},
+}
+
+// contextStats returns the usage of each context, busiest first, followed by
+// the contexts that weren't used at all
+func contextStats(contextNames []string, events []history.Event, since, now time.Time, unusedDays int) []output.ContextStats {
+	unused := make(map[string]time.Time)
+	if unusedDays > 0 {
+		unused = history.Unused(contextNames, events, now.AddDate(0, 0, -unusedDays))
+	}
+
+	exists := make(map[string]bool, len(contextNames))
+	for _, name := range contextNames {
+		exists[name] = true
+	}
+
+	stats := make([]output.ContextStats, 0, len(contextNames))
+	seen := make(map[string]bool)
+	for _, usage := range history.Summarize(events, since, now) {
+		// Contexts that were deleted or filtered out aren't reported
+		if !exists[usage.Name] {
+			continue
+		}
+		seen[usage.Name] = true
+
+		_, isUnused := unused[usage.Name]
+		ctx := output.ContextStats{UsageStats: usageStats(usage.Usage), Unused: isUnused}
+		for _, ns := range usage.Namespaces {
+			ctx.Namespaces = append(ctx.Namespaces, usageStats(ns))
+		}
+		stats = append(stats, ctx)
+	}
+
+	for _, name := range contextNames {
+		if seen[name] {
+			continue
+		}
+		_, isUnused := unused[name]
+		stats = append(stats, output.ContextStats{UsageStats: output.UsageStats{Name: name}, Unused: isUnused})
+	}
+	return stats
+}
+
+// usageStats converts history usage to its output document
+func usageStats(usage history.Usage) output.UsageStats {
+	return output.UsageStats{
+		Name:             usage.Name,
+		Switches:         usage.Switches,
+		LastUsed:         usage.LastUsed,
+		TimeSpentSeconds: int64(usage.TimeSpent / time.Second),
+	}
+}
+
+// printStats prints the usage of every context and the unused ones
+func printStats(stats output.Stats) {
+	header := "Context usage:"
+	if !stats.Since.IsZero() {
+		header = fmt.Sprintf("Context usage since %s:", stats.Since.Local().Format(time.DateTime))
+	}
+
+	var items, unused []string
+	for _, ctx := range stats.Contexts {
+		if ctx.Unused {
+			unused = append(unused, ctx.Name)
+		}
+		if ctx.Switches == 0 && ctx.TimeSpentSeconds == 0 {
+			continue
+		}
+		items = append(items, fmt.Sprintf("%-24s %s", ctx.Name, usageSummary(ctx.UsageStats)))
+		for _, ns := range ctx.Namespaces {
+			items = append(items, fmt.Sprintf("  %-22s %s", ns.Name, usageSummary(ns)))
+		}
+	}
+
+	if len(items) == 0 {
+		ui.PrintNote("No switches recorded", "(kontext records every switch it makes)")
+	} else {
+		ui.PrintList(header, items)
+	}
+
+	if len(unused) > 0 {
+		ui.PrintList(fmt.Sprintf("Not used in %d days (see 'kontext prune --unused-days %d'):", stats.UnusedDays, stats.UnusedDays), unused)
+	}
+}
+
+// usageSummary describes a usage in one line
+func usageSummary(usage output.UsageStats) string {
+	switches := "switches"
+	if usage.Switches == 1 {
+		switches = "switch  "
+	}
+	summary := fmt.Sprintf("%4d %s  %8s", usage.Switches, switches, formatTimeSpent(time.Duration(usage.TimeSpentSeconds)*time.Second))
+	if !usage.LastUsed.IsZero() {
+		summary += "  last used " + usage.LastUsed.Local().Format(time.DateTime)
+	}
+	return summary
+}
+
+// formatTimeSpent formats a duration in days, hours and minutes
+func formatTimeSpent(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	// Add flags
+	statsCmd.Flags().String("since", "", "Only count usage after this time or duration ago (e.g. 30d or 2025-01-31)")
+	statsCmd.Flags().Int("unused-days", 30, "Highlight contexts not used in this many days (0 disables)")
+	addTagFlag(statsCmd)
+	addOutputFlag(statsCmd)
+}
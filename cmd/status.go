@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/output"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the current context, namespace and cluster",
+	Long: `Show a summary of where kubectl commands will go: the current context,
+its namespace, cluster, server, user and authentication method, and the
+kubeconfig file it comes from. For a time-boxed switch (switch --for) it
+also shows how long until kontext switches back.
+
+Examples:
+  # Show the current status
+  kontext status
+
+  # Machine-readable output
+  kontext status -o json
+  kontext status -o wide`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := getOutputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		m := newManager()
+		config, err := m.Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+
+		if config.CurrentContext == "" {
+			return newCommandError("Error retrieving current context", kubeconfig.ErrNoCurrentContext)
+		}
+
+		details, err := kubeconfig.DescribeContext(config, config.CurrentContext, false)
+		if err != nil {
+			return newCommandError("Error retrieving current context", err)
+		}
+
+		status := output.Status{ContextInfo: output.NewContextInfo(details), Source: details.Source}
+		if elevation := currentExpiry(config.CurrentContext); elevation != nil {
+			status.Expiry = &output.Expiry{
+				Expires:           elevation.Expires,
+				Remaining:         elevation.Remaining(m.Now()).Round(time.Second).String(),
+				FallbackContext:   elevation.FallbackContext,
+				FallbackNamespace: elevation.FallbackNamespace,
+			}
+		}
+
+		if !format.IsText() {
+			return printObject(format, status)
+		}
+
+		ui.PrintCurrentNamespace(status.Name, status.Namespace)
+		ui.PrintField("Cluster", status.Cluster)
+		ui.PrintField("Server", status.Server)
+		ui.PrintField("User", status.User)
+		ui.PrintField("Authentication", status.AuthMethod)
+		ui.PrintField("Source", status.Source)
+		if status.Expiry != nil {
+			ui.PrintField("Expires", fmt.Sprintf("in %s, then back to %s", status.Expiry.Remaining, status.Expiry.FallbackContext))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	// Add flags
+	addOutputFlag(statusCmd)
+}
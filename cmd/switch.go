@@ -1,132 +1,292 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/hooks"
 	"github.com/user-cube/kontext/pkg/kubeconfig"
 	"github.com/user-cube/kontext/pkg/ui"
 )
 
 // runSwitch contains the main logic for the switch command
-// This is shared with the root command to enable the same behavior with `kontext`
-func runSwitch(cmd *cobra.Command, args []string) {
-	var contextName string
+// This is shared with the root command to enable the same behavior with `kontext`.
+// Every step works on the same kubeconfig snapshot, so it is only parsed once,
+// and the context and namespace are written together.
+func runSwitch(cmd *cobra.Command, args []string) error {
+	// Get the list of non-flag arguments (context and possibly namespace)
+	nonFlagArgs := []string{}
+	for _, arg := range args {
+		// Check if this arg looks like a flag
+		if !strings.HasPrefix(arg, "-") {
+			nonFlagArgs = append(nonFlagArgs, arg)
+		}
+	}
 
-	// Get available contexts
-	contexts, err := kubeconfig.GetContexts()
-	if err != nil {
-		ui.PrintError("Error retrieving contexts", err, true)
+	// Check if a namespace is specified after the -n flag
+	setNS, _ := cmd.Flags().GetBool("set-namespace")
+	var namespaceArg string
+
+	// Get all args that might contain the -n flag and a namespace argument
+	allArgs := os.Args
+	if setNS {
+		// Look for a namespace argument after the -n flag
+		for i, arg := range allArgs {
+			if (arg == "-n" || arg == "--set-namespace") && i+1 < len(allArgs) && !strings.HasPrefix(allArgs[i+1], "-") {
+				// Check if the next arg isn't another flag and isn't part of the context name
+				if len(nonFlagArgs) == 0 || allArgs[i+1] != nonFlagArgs[0] {
+					namespaceArg = allArgs[i+1]
+					break
+				}
+			}
+		}
+	}
+
+	if create, _ := cmd.Flags().GetBool("create-namespace"); create && namespaceArg == "" {
+		return fmt.Errorf("--create-namespace requires a namespace name, as in -n my-namespace")
+	}
+	if _, err := getForDuration(cmd); err != nil {
+		return err
 	}
 
-	currentContext, err := kubeconfig.GetCurrentContext()
+	m := newManager()
+	contextName, err := selectContext(cmd, m, nonFlagArgs)
 	if err != nil {
-		ui.PrintError("Error retrieving current context", err, true)
+		return err
 	}
 
-	// Get the current namespace
-	currentNamespace, err := kubeconfig.GetCurrentNamespace()
+	if setNS && namespaceArg == "" {
+		// No namespace argument but -n flag was specified: select the
+		// namespace of the new context before switching to it
+		config, err := m.Config()
+		if err != nil {
+			return newCommandError("Error retrieving contexts", err)
+		}
+		if err := confirmProtectedSwitch(cmd, contextName, config.CurrentContext); err != nil {
+			return err
+		}
+		targetNamespace, _ := m.GetNamespaceForContext(contextName)
+		if namespaceArg, err = selectNamespace(m, contextName, targetNamespace); err != nil {
+			return err
+		}
+	}
+
+	return applySwitch(cmd, m, contextName, namespaceArg)
+}
+
+// selectContext returns the context named by the first argument, resolving
+// aliases, or the one picked in the interactive selector without arguments
+func selectContext(cmd *cobra.Command, m *kubeconfig.Manager, args []string) (string, error) {
+	// Get available contexts
+	config, err := m.Config()
 	if err != nil {
-		// Non-fatal - default to "default" namespace
-		currentNamespace = "default"
+		return "", newCommandError("Error retrieving contexts", err)
 	}
+	contexts := config.Contexts
+	currentContext := config.CurrentContext
 
 	if len(args) == 0 {
+		if err := requireInput("A context name is required"); err != nil {
+			return "", err
+		}
+
 		// If no context is provided, show interactive selector
 		contextNames := make([]string, 0, len(contexts))
 		for name := range contexts {
 			contextNames = append(contextNames, name)
 		}
+		contextNames, err = filterByTags(cmd, contextNames)
+		if err != nil {
+			return "", err
+		}
+		applyGroupBy(cmd, contextNames)
 
 		// Sort context names and optionally prioritize current context
 		// Setting the third parameter to true would place current context first
 		// Setting it to false maintains alphabetical order
-		contextNames = ui.SortContexts(contextNames, currentContext, true)
+		contextNames = ui.SortContexts(contextNames, currentContext, settings.Selector.CurrentFirst)
 
 		// Create the selector and run it
 		selector := ui.CreateContextSelector(contextNames, currentContext)
-		_, selection, err := selector.Run()
-
+		contextName, err := ui.RunSelector(selector)
 		if err != nil {
-			ui.PrintError("Selection canceled", err, false)
-			return
+			return "", newCommandError("Context selection failed", err)
 		}
+		return contextName, nil
+	}
 
-		contextName = selection
-
-		// Don't switch if selected context is already current
-		if contextName == currentContext {
-			ui.PrintWarning(fmt.Sprintf("Context '%s' is already selected", contextName))
-			ui.PrintCurrentNamespace(contextName, currentNamespace)
-			return
-		}
+	contextName := resolveContextArg(config, args[0])
 
-		// Switch to the selected context
-		err = kubeconfig.SwitchContext(contextName)
-		if err != nil {
-			ui.PrintError("Error switching context", err, true)
+	// Check if the context exists
+	if _, exists := contexts[contextName]; !exists {
+		// Get available context names for the error message
+		contextNames := make([]string, 0, len(contexts))
+		for name := range contexts {
+			contextNames = append(contextNames, name)
 		}
+		// Sort contexts with the current context highlighted
+		contextNames = ui.SortContexts(contextNames, currentContext, false)
 
-		ui.PrintSuccess("Switched to context", contextName)
-
-		// Get namespace for the new context
-		targetNamespace, err := kubeconfig.GetNamespaceForContext(contextName)
-		if err != nil {
-			// Non-fatal - default to "default" namespace
-			targetNamespace = "default"
+		return "", &commandError{
+			Msg:     "Error switching context",
+			Err:     fmt.Errorf("%w: '%s'", kubeconfig.ErrContextNotFound, contextName),
+			Details: func() { ui.PrintContextList(contextNames, currentContext) },
 		}
+	}
+	return contextName, nil
+}
 
-		ui.PrintSuccess("Namespace", targetNamespace)
+// applySwitch switches to a context, and to namespaceArg in it when given, as
+// a single change
+//
+// It confirms protected contexts, picks the namespace (namespaceArg, then
+// --keep-namespace and --restore-namespace), runs the hooks around the switch
+// and records it in the history, the audit log and the time-boxed switch.
+func applySwitch(cmd *cobra.Command, m *kubeconfig.Manager, contextName, namespaceArg string) error {
+	config, err := m.Config()
+	if err != nil {
+		return newCommandError("Error retrieving contexts", err)
+	}
+	currentContext := config.CurrentContext
 
-		// The namespace selector will be handled by the caller if needed
-		// We don't want to call it here to avoid duplicate namespace selection
-	} else {
-		contextName = args[0]
+	// Get the current namespace
+	currentNamespace, err := m.GetCurrentNamespace()
+	if err != nil {
+		// Non-fatal - default to "default" namespace
+		currentNamespace = "default"
+	}
 
-		// Check if the context exists
-		if _, exists := contexts[contextName]; !exists {
-			// Get available context names for the error message
-			contextNames := make([]string, 0, len(contexts))
-			for name := range contexts {
-				contextNames = append(contextNames, name)
-			}
-			// Sort contexts with the current context highlighted
-			contextNames = ui.SortContexts(contextNames, currentContext, false)
+	if err := confirmProtectedSwitch(cmd, contextName, currentContext); err != nil {
+		return err
+	}
 
-			ui.PrintError(fmt.Sprintf("Context '%s' does not exist", contextName), nil, false)
-			ui.PrintContextList(contextNames, currentContext)
-			os.Exit(1)
+	// Don't switch if selected context is already current
+	if contextName == currentContext {
+		ui.PrintWarning(fmt.Sprintf("Context '%s' is already selected", contextName))
+		ui.PrintCurrentNamespace(contextName, currentNamespace)
+		updateElevation(cmd, m, contextName, currentContext, currentNamespace)
+		if namespaceArg == "" {
+			return nil
 		}
+		return switchNamespace(cmd, m, currentContext, currentNamespace, namespaceArg)
+	}
 
-		// Don't switch if selected context is already current
-		if contextName == currentContext {
-			ui.PrintWarning(fmt.Sprintf("Context '%s' is already selected", contextName))
-			ui.PrintCurrentNamespace(contextName, currentNamespace)
-			return
+	// Get namespace for the new context
+	targetNamespace, err := m.GetNamespaceForContext(contextName)
+	if err != nil {
+		// Non-fatal - default to "default" namespace
+		targetNamespace = "default"
+	}
+	chosenNamespace := namespaceArg
+	if chosenNamespace == "" {
+		chosenNamespace = keepNamespace(cmd, m, contextName, currentNamespace, targetNamespace)
+		if chosenNamespace == "" {
+			chosenNamespace = restoreNamespace(cmd, m, contextName, targetNamespace)
 		}
+	}
+	if chosenNamespace != "" {
+		targetNamespace = chosenNamespace
+	}
 
-		// Switch to the selected context
-		err = kubeconfig.SwitchContext(contextName)
-		if err != nil {
-			ui.PrintError("Error switching context", err, true)
+	change := hooks.Change{
+		Kind:         "context",
+		OldContext:   currentContext,
+		OldNamespace: currentNamespace,
+		NewContext:   contextName,
+		NewNamespace: targetNamespace,
+	}
+	if err := runPreHooks(change); err != nil {
+		return err
+	}
+
+	// Check or create the namespace only after the pre hooks, which may veto
+	// the switch or refresh the credentials
+	if namespaceArg != "" {
+		if err := ensureNamespace(cmd, m, contextName, namespaceArg); err != nil {
+			return err
 		}
+	}
 
-		ui.PrintSuccess("Switched to context", contextName)
+	// Switch context and namespace in the loaded kubeconfig and write it once,
+	// so an interrupted switch never leaves only half of it applied
+	config.CurrentContext = contextName
+	if chosenNamespace != "" {
+		config.Contexts[contextName].Namespace = chosenNamespace
+	}
+	if err := m.Save(); err != nil {
+		return newCommandError("Error switching context", err)
+	}
+	if chosenNamespace != "" {
+		recordNamespace(contextName, chosenNamespace)
+	}
 
-		// Get namespace for the new context
-		targetNamespace, err := kubeconfig.GetNamespaceForContext(contextName)
-		if err != nil {
-			// Non-fatal - default to "default" namespace
-			targetNamespace = "default"
-		}
+	ui.PrintSuccess("Switched to context", contextName)
+	ui.PrintSuccess("Namespace", targetNamespace)
+	recordSwitch(m, contextName, targetNamespace, currentContext, currentNamespace)
+	auditChange(m, change, "")
+	updateElevation(cmd, m, contextName, currentContext, currentNamespace)
+	runPostHooks(change)
+	return nil
+}
 
-		ui.PrintSuccess("Namespace", targetNamespace)
+// keepNamespace returns the namespace to set on the target context with
+// --keep-namespace (or namespaces.keep): the current namespace if it exists in
+// the target cluster. It returns "" when there is nothing to change, with a
+// warning when the namespace can't be kept.
+func keepNamespace(cmd *cobra.Command, m *kubeconfig.Manager, contextName, currentNamespace, targetNamespace string) string {
+	keep := settings.Namespaces.Keep
+	if cmd.Flags().Changed("keep-namespace") {
+		keep, _ = cmd.Flags().GetBool("keep-namespace")
+	}
+	if !keep || currentNamespace == "" || currentNamespace == targetNamespace {
+		return ""
+	}
 
-		// The namespace selector will be handled by the caller if needed
-		// We don't want to call it here to avoid duplicate namespace selection
+	namespaces, err := m.ListNamespacesForContext(contextName)
+	if err == nil || errors.Is(err, kubeconfig.ErrClusterUnreachable) {
+		recordProbe(m, contextName, err)
 	}
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Could not check namespace '%s' in context '%s'", currentNamespace, contextName),
+			fmt.Sprintf("(using %s)", targetNamespace))
+		ui.Debugf("listing namespaces failed: %v", err)
+		return ""
+	}
+	if !slices.Contains(namespaces, currentNamespace) {
+		ui.PrintWarning(fmt.Sprintf("Namespace '%s' does not exist in context '%s'", currentNamespace, contextName),
+			fmt.Sprintf("(using %s)", targetNamespace))
+		return ""
+	}
+	return currentNamespace
+}
+
+// restoreNamespace returns the namespace last chosen in the target context
+// with --restore-namespace (or namespaces.restore), or "" when there is nothing
+// to change. Without it, the namespace is only offered when the kubeconfig has
+// none for the context, as happens when other tools rewrite it.
+func restoreNamespace(cmd *cobra.Command, m *kubeconfig.Manager, contextName, targetNamespace string) string {
+	last := lastNamespace(contextName)
+	if last == "" || last == targetNamespace {
+		return ""
+	}
+
+	restore := settings.Namespaces.Restore
+	if cmd.Flags().Changed("restore-namespace") {
+		restore, _ = cmd.Flags().GetBool("restore-namespace")
+	}
+	if restore {
+		return last
+	}
+
+	if namespaceUnset(m, contextName) {
+		ui.PrintNote(fmt.Sprintf("Namespace '%s' was last used in context '%s'", last, contextName),
+			"(pass --restore-namespace to switch to it)")
+	}
+	return ""
 }
 
 // switchCmd represents the switch command
@@ -142,6 +302,9 @@ Examples:
   
   # Switch to specific context by name
   kontext switch my-context
+
+  # Only offer production contexts, grouped by region
+  kontext switch -t env=prod --group-by region
   
   # Switch to context and then select namespace interactively
   kontext switch -n
@@ -151,6 +314,21 @@ Examples:
   
   # Switch to specific context and directly set a namespace
   kontext switch my-context -n my-namespace
+
+  # Switch to a protected context without the confirmation prompt
+  kontext switch prod --force
+
+  # Move from dev-eu to dev-us, staying in the same namespace
+  kontext switch dev-us --keep-namespace
+
+  # Go back to the namespace you last chose in my-context
+  kontext switch my-context --restore-namespace
+
+  # Switch and create the namespace if it doesn't exist yet
+  kontext switch my-context -n my-feature --create-namespace
+
+  # Switch to prod for 30 minutes, then automatically back
+  kontext switch prod --for 30m
   
   # The root command also acts as an alias to switch
   kontext
@@ -159,100 +337,34 @@ Examples:
   kontext my-context -n
   kontext my-context -n my-namespace`,
 	ValidArgsFunction: contextCompletion,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Get the list of non-flag arguments (context and possibly namespace)
-		nonFlagArgs := []string{}
-		for _, arg := range args {
-			// Check if this arg looks like a flag
-			if !strings.HasPrefix(arg, "-") {
-				nonFlagArgs = append(nonFlagArgs, arg)
-			}
-		}
-
-		// Check if a namespace is specified after the -n flag
-		setNS, _ := cmd.Flags().GetBool("set-namespace")
-		var namespaceArg string
-
-		// Get all args that might contain the -n flag and a namespace argument
-		allArgs := os.Args
-		if setNS {
-			// Look for a namespace argument after the -n flag
-			for i, arg := range allArgs {
-				if (arg == "-n" || arg == "--set-namespace") && i+1 < len(allArgs) && !strings.HasPrefix(allArgs[i+1], "-") {
-					// Check if the next arg isn't another flag and isn't part of the context name
-					if len(nonFlagArgs) == 0 || allArgs[i+1] != nonFlagArgs[0] {
-						namespaceArg = allArgs[i+1]
-						break
-					}
-				}
-			}
-		}
-
-		// If we found a namespace arg, run with it; otherwise use the normal runSwitch
-		if namespaceArg != "" {
-			// If we have a context, switch to it first, then set the namespace directly
-			if len(nonFlagArgs) > 0 {
-				// Create args with just the context
-				contextArgs := []string{nonFlagArgs[0]}
-				// Call runSwitch with just the context
-				runSwitch(cmd, contextArgs)
-
-				// Before setting the namespace, check if it exists
-				newContext, err := kubeconfig.GetCurrentContext()
-				if err != nil {
-					ui.PrintError("Error retrieving current context", err, true)
-				}
-
-				namespaces, err := kubeconfig.GetNamespaces()
-				if err != nil {
-					ui.PrintError("Error retrieving namespaces", err, true)
-				}
-
-				// Check if the specified namespace exists
-				namespaceExists := false
-				for _, ns := range namespaces {
-					if ns == namespaceArg {
-						namespaceExists = true
-						break
-					}
-				}
-
-				if !namespaceExists {
-					ui.PrintNote(fmt.Sprintf("Namespace '%s' does not exist in context '%s'", namespaceArg, newContext),
-						"(continuing with the requested namespace)")
-					// Continue anyway since the user explicitly requested this namespace
-				}
-
-				// Then set the namespace directly
-				nsCmd.Run(nsCmd, []string{namespaceArg})
-			} else {
-				// If no context specified, show context selector and then set namespace
-				runSwitch(cmd, []string{})
-				nsCmd.Run(nsCmd, []string{namespaceArg})
-			}
-		} else if setNS {
-			// No namespace argument but -n flag was specified
-			// First switch context if needed
-			runSwitch(cmd, nonFlagArgs)
-			// Then run namespace selector
-			nsCmd.Run(nsCmd, []string{})
-		} else {
-			// No namespace argument found, use normal flow
-			runSwitch(cmd, nonFlagArgs)
-		}
-	},
+	RunE:              runSwitch,
 }
 
-// contextCompletion provides autocompletion for context names
+// contextCompletion provides autocompletion for context names and aliases
+// Contexts are described by their aliases and aliases by their context.
 func contextCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	contexts, err := kubeconfig.GetContexts()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
 
+	aliasesOf := make(map[string][]string)
 	var suggestions []string
+	for _, alias := range settings.AliasNames() {
+		contextName, _ := settings.ResolveAlias(alias)
+		if _, exists := contexts[alias]; exists {
+			continue
+		}
+		aliasesOf[contextName] = append(aliasesOf[contextName], alias)
+		suggestions = append(suggestions, alias+"\talias for "+contextName)
+	}
+
 	for name := range contexts {
-		suggestions = append(suggestions, name)
+		if aliases := aliasesOf[name]; len(aliases) > 0 {
+			suggestions = append(suggestions, name+"\talias "+strings.Join(aliases, ", "))
+		} else {
+			suggestions = append(suggestions, name)
+		}
 	}
 
 	return suggestions, cobra.ShellCompDirectiveNoFileComp
@@ -263,4 +375,11 @@ func init() {
 
 	// Add flags
 	switchCmd.Flags().BoolP("set-namespace", "n", false, "Also set the namespace after switching context")
+	switchCmd.Flags().Bool("keep-namespace", false, "Stay in the current namespace if it exists in the new context (default from namespaces.keep in the config)")
+	switchCmd.Flags().Bool("restore-namespace", false, "Switch to the namespace last chosen in the new context (default from namespaces.restore in the config)")
+	switchCmd.Flags().Bool("create-namespace", false, "Create the namespace given with -n if it doesn't exist (labels from namespaces.labels in the config)")
+	addForceFlag(switchCmd, "Switch to a protected context without confirmation")
+	addForFlag(switchCmd)
+	addTagFlag(switchCmd)
+	addGroupByFlag(switchCmd)
 }
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/user-cube/kontext/pkg/config"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+	"github.com/user-cube/kontext/pkg/ui"
+)
+
+// addTagFlag registers the shared -t/--tag filter flag on a command
+func addTagFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("tag", "t", "", "Only consider contexts whose tags match, e.g. env=prod,region=eu (also k!=v, k, !k)")
+}
+
+// addGroupByFlag registers the shared --group-by flag on a command
+func addGroupByFlag(cmd *cobra.Command) {
+	cmd.Flags().String("group-by", "", "Group contexts under headings by the value of a tag key")
+}
+
+// getTagSelector returns the tag filter selected with -t
+// An invalid expression is a usage error.
+func getTagSelector(cmd *cobra.Command) (config.TagSelector, error) {
+	expr, _ := cmd.Flags().GetString("tag")
+	return config.ParseTagSelector(expr)
+}
+
+// filterByTags returns the contexts matching the -t filter
+// It fails when a filter is set but no context matches it.
+func filterByTags(cmd *cobra.Command, contextNames []string) ([]string, error) {
+	selector, err := getTagSelector(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return contextNames, nil
+	}
+
+	matched := settings.FilterContexts(contextNames, selector)
+	if len(matched) == 0 {
+		return nil, newCommandError("Error filtering contexts",
+			fmt.Errorf("%w: tags '%s'", kubeconfig.ErrNoMatchingContexts, selector))
+	}
+	return matched, nil
+}
+
+// applyGroupBy groups contexts in lists and selectors by the --group-by tag key
+func applyGroupBy(cmd *cobra.Command, contextNames []string) {
+	key, _ := cmd.Flags().GetString("group-by")
+	if key == "" {
+		ui.SetContextGroups(nil)
+		return
+	}
+	ui.SetContextGroups(settings.GroupContexts(contextNames, key))
+}
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Tag contexts to filter and group them",
+	Long: `Tag contexts with key=value pairs (or bare keys) to filter and group them.
+
+Tags are stored in kontext's configuration file. Commands that work on
+several contexts accept -t/--tag to only consider matching contexts, and
+list and switch accept --group-by to group contexts by a tag key.
+
+Examples:
+  kontext tag add prod-eu env=prod region=eu
+  kontext tag rm prod-eu region
+  kontext tag list
+  kontext list -t env=prod,region=eu
+  kontext switch -t env!=prod --group-by region
+  kontext delete -t env=preview --dry-run`,
+}
+
+// tagAddCmd represents the tag add command
+var tagAddCmd = &cobra.Command{
+	Use:               "add <context> <key=value|key>...",
+	Short:             "Add tags to a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: tagContextCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+		contextName := resolveContextArg(kubeConfig, args[0])
+		if _, exists := kubeConfig.Contexts[contextName]; !exists {
+			return newCommandError("Error tagging context", fmt.Errorf("%w: '%s'", kubeconfig.ErrContextNotFound, contextName))
+		}
+
+		tags, err := config.ParseTags(args[1:])
+		if err != nil {
+			return newCommandError("Error tagging context", err)
+		}
+
+		err = saveConfig("tags", func(file *config.Config) error {
+			file.SetTags(contextName, tags)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		ui.PrintSuccess(fmt.Sprintf("Tagged %s with", contextName), config.FormatTags(settings.TagsFor(contextName)))
+		return nil
+	},
+}
+
+// tagRmCmd represents the tag rm command
+var tagRmCmd = &cobra.Command{
+	Use:               "rm <context> <key>...",
+	Aliases:           []string{"remove"},
+	Short:             "Remove tags from a context",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: tagContextCompletion,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kubeConfig, err := newManager().Config()
+		if err != nil {
+			return newCommandError("Error loading kubeconfig", err)
+		}
+		// Tags of contexts that no longer exist can still be removed
+		contextName := resolveContextArg(kubeConfig, args[0])
+		keys := args[1:]
+
+		for _, key := range keys {
+			if _, exists := settings.TagsFor(contextName)[key]; !exists {
+				ui.PrintWarning(fmt.Sprintf("Context '%s' has no tag '%s'", contextName, key))
+			}
+		}
+
+		err = saveConfig("tags", func(file *config.Config) error {
+			file.RemoveTags(contextName, keys)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if remaining := settings.TagsFor(contextName); len(remaining) > 0 {
+			ui.PrintSuccess(fmt.Sprintf("Tags of %s are now", contextName), config.FormatTags(remaining))
+		} else {
+			ui.PrintSuccess("Context has no tags left", contextName)
+		}
+		return nil
+	},
+}
+
+// tagListCmd represents the tag list command
+var tagListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List tagged contexts",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(settings.Tags) == 0 {
+			ui.PrintNote("No contexts are tagged", "(add tags with 'kontext tag add <context> key=value')")
+			return nil
+		}
+
+		contextNames := make([]string, 0, len(settings.Tags))
+		for name := range settings.Tags {
+			contextNames = append(contextNames, name)
+		}
+		sort.Strings(contextNames)
+
+		contextNames, err := filterByTags(cmd, contextNames)
+		if err != nil {
+			return err
+		}
+
+		items := make([]string, 0, len(contextNames))
+		for _, name := range contextNames {
+			items = append(items, fmt.Sprintf("%s  %s", name, config.FormatTags(settings.TagsFor(name))))
+		}
+		ui.PrintList("Tagged contexts:", items)
+		return nil
+	},
+}
+
+// tagContextCompletion completes the context of tag add and tag rm
+func tagContextCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return contextCompletion(cmd, nil, toComplete)
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd, tagRmCmd, tagListCmd)
+
+	addTagFlag(tagListCmd)
+}
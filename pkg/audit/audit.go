@@ -0,0 +1,320 @@
+// Package audit records every change kontext makes to the kubeconfig
+//
+// The audit log is an append-only JSONL file, one entry per change, stating
+// who made it, from where and what changed. It is rotated by size: when the
+// log would grow past its limit it is renamed to audit.jsonl.1, older files
+// are shifted to .2, .3 and so on, and the oldest is removed.
+//
+// The log lives in kontext's state directory unless another path is configured.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user-cube/kontext/pkg/history"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+)
+
+// Operations recorded in the audit log
+const (
+	// OpSwitch is a change of the current context
+	OpSwitch = "switch"
+	// OpNamespace is a change of the current context's namespace
+	OpNamespace = "namespace"
+	// OpDelete is the removal of contexts with kontext delete
+	OpDelete = "delete"
+	// OpPrune is the removal of entries with kontext prune
+	OpPrune = "prune"
+	// OpLint is the removal of orphan entries with kontext lint --fix
+	OpLint = "lint"
+)
+
+// Operations lists every operation, in the order they are documented
+var Operations = []string{OpSwitch, OpNamespace, OpDelete, OpPrune, OpLint}
+
+// Entry is a single change recorded in the audit log
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	User       string    `json:"user"`
+	Host       string    `json:"host"`
+	TTY        string    `json:"tty,omitempty"`
+	Kubeconfig string    `json:"kubeconfig"`
+	// Reason explains changes kontext made on its own, such as "expired"
+	Reason       string `json:"reason,omitempty"`
+	OldContext   string `json:"oldContext,omitempty"`
+	OldNamespace string `json:"oldNamespace,omitempty"`
+	NewContext   string `json:"newContext,omitempty"`
+	NewNamespace string `json:"newNamespace,omitempty"`
+	// Contexts, Clusters and Users list the entries removed by delete, prune and lint
+	Contexts []string `json:"contexts,omitempty"`
+	Clusters []string `json:"clusters,omitempty"`
+	Users    []string `json:"users,omitempty"`
+}
+
+// NewEntry returns an entry for an operation on a kubeconfig, identifying the
+// user, host and terminal running kontext
+func NewEntry(operation, kubeconfigPath string) Entry {
+	entry := Entry{
+		Operation:  operation,
+		User:       CurrentUser(),
+		Kubeconfig: kubeconfigPath,
+		TTY:        currentTTY(),
+	}
+	entry.Host, _ = os.Hostname()
+	return entry
+}
+
+// Log is an audit log file with its rotation settings
+type Log struct {
+	// Path is the active log file
+	Path string
+	// MaxSize is the size in bytes after which the log is rotated (0 never rotates)
+	MaxSize int64
+	// MaxFiles is the number of rotated files kept
+	MaxFiles int
+}
+
+// DefaultPath returns the path of the audit log in kontext's state directory
+func DefaultPath() string {
+	return filepath.Join(history.GetStateDir(), "audit.jsonl")
+}
+
+// Append writes an entry to the log, rotating it first if it would grow past MaxSize
+// A zero entry time is replaced with the current time.
+func (l Log) Append(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Time = entry.Time.UTC()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o700); err != nil {
+		return fmt.Errorf("error creating audit log directory: %w", err)
+	}
+	if err := l.rotate(int64(len(data))); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening audit log: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts the log files if writing size more bytes would exceed MaxSize
+func (l Log) rotate(size int64) error {
+	if l.MaxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(l.Path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.Size()+size <= l.MaxSize) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error checking audit log: %w", err)
+	}
+
+	// Drop the oldest file, and any left over from a larger MaxFiles
+	for i := l.MaxFiles; ; i++ {
+		err := os.Remove(l.rotatedPath(i))
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error rotating audit log: %w", err)
+		}
+	}
+	for i := l.MaxFiles - 1; i >= 0; i-- {
+		err := os.Rename(l.rotatedPath(i), l.rotatedPath(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error rotating audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+// rotatedPath returns the path of the i-th rotated file, the active log for 0
+func (l Log) rotatedPath(i int) string {
+	if i == 0 {
+		return l.Path
+	}
+	return l.Path + "." + strconv.Itoa(i)
+}
+
+// Load returns every entry of the log and its rotated files, oldest first
+// Lines that cannot be parsed are skipped.
+func (l Log) Load() ([]Entry, error) {
+	count := 0
+	for {
+		if _, err := os.Stat(l.rotatedPath(count + 1)); err != nil {
+			break
+		}
+		count++
+	}
+
+	var entries []Entry
+	for i := count; i >= 0; i-- {
+		loaded, err := loadFile(l.rotatedPath(i))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, loaded...)
+	}
+	return entries, nil
+}
+
+// loadFile reads the entries of a single log file
+// A missing file has no entries.
+func loadFile(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading audit log %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Filter selects audit entries
+// Zero fields don't filter.
+type Filter struct {
+	Since     time.Time
+	Until     time.Time
+	Operation string
+	// Context is a context name or shell pattern matched against the old,
+	// new and removed contexts of an entry
+	Context string
+}
+
+// Match reports whether an entry passes the filter
+func (f Filter) Match(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+	if f.Operation != "" && entry.Operation != f.Operation {
+		return false
+	}
+	if f.Context == "" {
+		return true
+	}
+	for _, name := range append([]string{entry.OldContext, entry.NewContext}, entry.Contexts...) {
+		if name == "" {
+			continue
+		}
+		if matched, _ := kubeconfig.MatchPattern(name, f.Context); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the entries that pass the filter
+func (f Filter) Apply(entries []Entry) []Entry {
+	matched := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// CurrentUser returns the name of the user running kontext
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// currentTTY returns the terminal kontext runs in, or "" without one
+// It is only known on systems with /proc.
+func currentTTY() string {
+	for _, fd := range []string{"0", "1", "2"} {
+		target, err := os.Readlink("/proc/self/fd/" + fd)
+		if err == nil && (strings.HasPrefix(target, "/dev/pts/") || strings.HasPrefix(target, "/dev/tty")) {
+			return target
+		}
+	}
+	return ""
+}
+
+// Summary describes what an entry changed in one line
+func (e Entry) Summary() string {
+	var summary string
+	switch e.Operation {
+	case OpSwitch:
+		summary = contextNamespace(e.OldContext, e.OldNamespace) + " -> " + contextNamespace(e.NewContext, e.NewNamespace)
+	case OpNamespace:
+		summary = fmt.Sprintf("%s: %s -> %s", e.NewContext, e.OldNamespace, e.NewNamespace)
+	default:
+		var removed []string
+		for _, group := range []struct {
+			kind  string
+			names []string
+		}{{"contexts", e.Contexts}, {"clusters", e.Clusters}, {"users", e.Users}} {
+			if len(group.names) > 0 {
+				removed = append(removed, group.kind+" "+strings.Join(group.names, ", "))
+			}
+		}
+		summary = "removed " + strings.Join(removed, "; ")
+	}
+	if e.Reason != "" {
+		summary += " (" + e.Reason + ")"
+	}
+	return summary
+}
+
+// contextNamespace formats a context and namespace as context/namespace
+func contextNamespace(contextName, namespace string) string {
+	if contextName == "" {
+		contextName = "(none)"
+	}
+	if namespace == "" {
+		return contextName
+	}
+	return contextName + "/" + namespace
+}
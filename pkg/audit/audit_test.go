@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// tempLog returns a log in a temporary directory
+func tempLog(t *testing.T, maxSize int64, maxFiles int) (Log, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "kontext-audit-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	log := Log{Path: filepath.Join(tmpDir, "audit.jsonl"), MaxSize: maxSize, MaxFiles: maxFiles}
+	return log, func() { _ = os.RemoveAll(tmpDir) }
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	log, cleanup := tempLog(t, 0, 0)
+	defer cleanup()
+
+	entries, err := log.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Load() on empty log = %d entries, want 0", len(entries))
+	}
+
+	switchEntry := NewEntry(OpSwitch, "/home/me/.kube/config")
+	switchEntry.OldContext, switchEntry.NewContext = "dev", "prod"
+	if err := log.Append(switchEntry); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	deleteEntry := NewEntry(OpDelete, "/home/me/.kube/config")
+	deleteEntry.Contexts = []string{"old"}
+	if err := log.Append(deleteEntry); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err = log.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Load() = %d entries, want 2", len(entries))
+	}
+	if entries[0].Operation != OpSwitch || entries[0].NewContext != "prod" || entries[0].Time.IsZero() {
+		t.Errorf("first entry = %+v, want a timed switch to prod", entries[0])
+	}
+	if entries[0].User == "" || entries[0].Kubeconfig != "/home/me/.kube/config" {
+		t.Errorf("first entry = %+v, want the user and kubeconfig", entries[0])
+	}
+
+	info, err := os.Stat(log.Path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("audit log mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestRotate(t *testing.T) {
+	// Every entry is well over 100 bytes, so each append rotates
+	log, cleanup := tempLog(t, 100, 2)
+	defer cleanup()
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, name := range []string{"a", "b", "c", "d"} {
+		entry := NewEntry(OpSwitch, "/kubeconfig")
+		entry.Time = start.Add(time.Duration(i) * time.Minute)
+		entry.NewContext = name
+		if err := log.Append(entry); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	for _, path := range []string{log.Path, log.Path + ".1", log.Path + ".2"} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should exist: %v", filepath.Base(path), err)
+		}
+	}
+	if _, err := os.Stat(log.Path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("audit.jsonl.3 should have been removed, got %v", err)
+	}
+
+	entries, err := log.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.NewContext)
+	}
+	if len(names) != 3 || names[0] != "b" || names[1] != "c" || names[2] != "d" {
+		t.Errorf("Load() after rotation = %v, want [b c d]", names)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: start, Operation: OpSwitch, OldContext: "dev", NewContext: "prod-eu"},
+		{Time: start.Add(time.Hour), Operation: OpNamespace, OldContext: "prod-eu", NewContext: "prod-eu"},
+		{Time: start.Add(2 * time.Hour), Operation: OpDelete, Contexts: []string{"staging", "prod-us"}},
+		{Time: start.Add(3 * time.Hour), Operation: OpSwitch, OldContext: "prod-eu", NewContext: "dev"},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"no filter", Filter{}, 4},
+		{"since", Filter{Since: start.Add(time.Hour)}, 3},
+		{"until", Filter{Until: start.Add(time.Hour)}, 2},
+		{"operation", Filter{Operation: OpSwitch}, 2},
+		{"context", Filter{Context: "staging"}, 1},
+		{"context pattern", Filter{Context: "prod-*"}, 4},
+		{"combined", Filter{Context: "prod-*", Operation: OpSwitch, Since: start.Add(time.Minute)}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Apply(entries); len(got) != tt.want {
+				t.Errorf("Apply() = %d entries, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
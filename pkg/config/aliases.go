@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrAliasNotFound is returned when removing an alias that doesn't exist
+var ErrAliasNotFound = errors.New("alias not found")
+
+// SetAlias makes alias stand for a context, replacing any previous target
+func (c *Config) SetAlias(alias, contextName string) error {
+	if problems := validateAliases(map[string]string{alias: contextName}); len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, problems[0])
+	}
+	if c.Aliases == nil {
+		c.Aliases = make(map[string]string)
+	}
+	c.Aliases[alias] = contextName
+	return nil
+}
+
+// RemoveAlias removes an alias
+func (c *Config) RemoveAlias(alias string) error {
+	if _, exists := c.Aliases[alias]; !exists {
+		return fmt.Errorf("%w: '%s'", ErrAliasNotFound, alias)
+	}
+	delete(c.Aliases, alias)
+	return nil
+}
+
+// ResolveAlias returns the context an alias stands for
+func (c *Config) ResolveAlias(alias string) (string, bool) {
+	contextName, exists := c.Aliases[alias]
+	return contextName, exists
+}
+
+// AliasesFor returns the sorted aliases that stand for a context
+func (c *Config) AliasesFor(contextName string) []string {
+	var aliases []string
+	for _, alias := range sortedKeys(c.Aliases) {
+		if c.Aliases[alias] == contextName {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// AliasNames returns the sorted alias names
+func (c *Config) AliasNames() []string {
+	return sortedKeys(c.Aliases)
+}
+
+// validateAliases checks alias names and targets
+func validateAliases(aliases map[string]string) []string {
+	var problems []string
+	for _, alias := range sortedKeys(aliases) {
+		switch {
+		case alias == "" || strings.ContainsAny(alias, " \t\n*?["):
+			problems = append(problems, fmt.Sprintf("aliases: '%s' is not a valid alias, it can't be empty or contain spaces or *?[", alias))
+		case strings.HasPrefix(alias, "-"):
+			problems = append(problems, fmt.Sprintf("aliases: '%s' is not a valid alias, it can't start with '-'", alias))
+		case aliases[alias] == "":
+			problems = append(problems, fmt.Sprintf("aliases.%s: must name a context", alias))
+		}
+	}
+	return problems
+}
+
+// sortedKeys returns the keys of a map in order, for stable messages
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
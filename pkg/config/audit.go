@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+// Audit configures the audit log of kubeconfig changes
+type Audit struct {
+	// Enabled records every change kontext makes to the kubeconfig
+	Enabled bool `json:"enabled"`
+	// Path is the audit log file (empty for audit.jsonl in the state directory)
+	Path string `json:"path,omitempty"`
+	// MaxSize is the size in megabytes after which the log is rotated (0 never rotates)
+	MaxSize int `json:"maxSize"`
+	// MaxFiles is the number of rotated logs kept
+	MaxFiles int `json:"maxFiles"`
+}
+
+// validateAudit checks the rotation settings of the audit log
+func validateAudit(a Audit) []string {
+	var problems []string
+	if a.MaxSize < 0 {
+		problems = append(problems, fmt.Sprintf("audit.maxSize: can't be negative, got %d", a.MaxSize))
+	}
+	if a.MaxFiles < 0 {
+		problems = append(problems, fmt.Sprintf("audit.maxFiles: can't be negative, got %d", a.MaxFiles))
+	}
+	return problems
+}
@@ -0,0 +1,279 @@
+// Package config reads and writes kontext's user configuration
+//
+// The configuration is a YAML file at $XDG_CONFIG_HOME/kontext/config.yaml
+// (~/.config/kontext/config.yaml by default, or KONTEXT_CONFIG). Every
+// setting has a default, so the file is optional, and every setting can be
+// overridden with an environment variable for a single run.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user-cube/kontext/pkg/static"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigEnv overrides the path of the configuration file
+const ConfigEnv = "KONTEXT_CONFIG"
+
+var (
+	// ErrInvalidConfig is returned when the configuration file can't be parsed
+	// or a setting has an invalid value
+	ErrInvalidConfig = errors.New("invalid kontext configuration")
+	// ErrUnknownKey is returned by Get and Set for keys that don't exist
+	ErrUnknownKey = errors.New("unknown configuration key")
+)
+
+// Config is kontext's user configuration
+type Config struct {
+	// Color is when to color output: auto, always or never
+	Color string `json:"color"`
+	// ASCII uses plain ASCII symbols instead of unicode
+	ASCII bool `json:"ascii"`
+	// Selector configures the interactive selectors
+	Selector Selector `json:"selector"`
+	// Namespaces configures namespace selection
+	Namespaces Namespaces `json:"namespaces"`
+	// Timeouts configures how long kontext waits for clusters
+	Timeouts Timeouts `json:"timeouts"`
+	// Kubeconfig configures kubeconfig fragments
+	Kubeconfig Kubeconfig `json:"kubeconfig"`
+	// Aliases maps short names to context names
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Tags maps context names to their tags
+	Tags map[string]map[string]string `json:"tags,omitempty"`
+	// Protected marks contexts that need confirmation to switch to or change
+	Protected Protected `json:"protected"`
+	// Hooks are commands run before and after context and namespace changes
+	Hooks Hooks `json:"hooks"`
+	// Env lists environment variables exported for matching contexts by the shell integration
+	Env []EnvVars `json:"env,omitempty"`
+	// Audit configures the audit log of kubeconfig changes
+	Audit Audit `json:"audit"`
+}
+
+// Selector configures the interactive selectors
+type Selector struct {
+	// Size is the number of items shown at once
+	Size int `json:"size"`
+	// CurrentFirst lists the current context or namespace first instead of in alphabetical order
+	CurrentFirst bool `json:"currentFirst"`
+}
+
+// Namespaces configures namespace selection
+type Namespaces struct {
+	// Fallback is offered when a cluster's namespaces can't be listed
+	Fallback []string `json:"fallback"`
+	// Keep stays in the current namespace when switching to a context where it exists
+	Keep bool `json:"keep"`
+	// Restore switches to the namespace last chosen in a context, as remembered by kontext
+	Restore bool `json:"restore"`
+	// Labels are set on namespaces created with --create-namespace
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Timeouts configures how long kontext waits for clusters
+type Timeouts struct {
+	// Request limits API calls such as listing namespaces (0 keeps the client-go default)
+	Request Duration `json:"request"`
+	// Probe limits each reachability probe of `kontext prune --probe`
+	Probe Duration `json:"probe"`
+}
+
+// Duration is a time.Duration written as a string such as "5s" in the file
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(time.Duration(d).String())), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("duration must be a string such as \"5s\", got %s", data)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Duration returns d as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Color: "auto",
+		Selector: Selector{
+			Size:         10,
+			CurrentFirst: true,
+		},
+		Namespaces: Namespaces{
+			Fallback: slices.Clone(static.FallBackNamespace),
+		},
+		Timeouts: Timeouts{
+			Probe: Duration(5 * time.Second),
+		},
+		Kubeconfig: Kubeconfig{
+			Overlay: "~/.kube/kontext-overlay.yaml",
+		},
+		Protected: Protected{
+			Confirm: "name",
+		},
+		Hooks: Hooks{
+			Timeout: Duration(30 * time.Second),
+		},
+		Audit: Audit{
+			Enabled:  true,
+			MaxSize:  10,
+			MaxFiles: 5,
+		},
+	}
+}
+
+// GetConfigPath returns the path of the configuration file
+//
+// It checks KONTEXT_CONFIG first, then $XDG_CONFIG_HOME/kontext/config.yaml,
+// and falls back to ~/.config/kontext/config.yaml.
+func GetConfigPath() string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "kontext", "config.yaml")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "kontext", "config.yaml")
+}
+
+// Load returns the effective configuration
+// It reads the configuration file, applies environment overrides and validates the result.
+func Load() (*Config, error) {
+	config, err := LoadFile(GetConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	if err := config.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadFile reads a configuration file on top of the defaults
+// A missing file is not an error. Unknown keys are rejected so typos don't go unnoticed.
+func LoadFile(path string) (*Config, error) {
+	config := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	if err := decode(data, config); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+	return config, nil
+}
+
+// decode parses YAML into config, rejecting unknown keys
+// The JSON decoding layer is an implementation detail, so it is kept out of error messages.
+func decode(data []byte, config *Config) error {
+	jsonData, err := yaml.YAMLToJSONStrict(data)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return errors.New(strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// Save writes the configuration to a file, creating its directory if needed
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error encoding configuration: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating configuration directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks every setting and reports all invalid ones at once
+func (c *Config) Validate() error {
+	var problems []string
+
+	switch c.Color {
+	case "auto", "always", "never":
+	default:
+		problems = append(problems, fmt.Sprintf("color: must be auto, always or never, got '%s'", c.Color))
+	}
+	if c.Selector.Size < 1 || c.Selector.Size > 100 {
+		problems = append(problems, fmt.Sprintf("selector.size: must be between 1 and 100, got %d", c.Selector.Size))
+	}
+	if len(c.Namespaces.Fallback) == 0 {
+		problems = append(problems, "namespaces.fallback: must list at least one namespace")
+	}
+	for _, ns := range c.Namespaces.Fallback {
+		if strings.TrimSpace(ns) == "" {
+			problems = append(problems, "namespaces.fallback: namespaces can't be empty")
+			break
+		}
+	}
+	for _, key := range sortedKeys(c.Namespaces.Labels) {
+		errs := validation.IsQualifiedName(key)
+		errs = append(errs, validation.IsValidLabelValue(c.Namespaces.Labels[key])...)
+		if len(errs) > 0 {
+			problems = append(problems, fmt.Sprintf("namespaces.labels: invalid label '%s=%s': %s", key, c.Namespaces.Labels[key], strings.Join(errs, "; ")))
+		}
+	}
+	if c.Timeouts.Request < 0 {
+		problems = append(problems, fmt.Sprintf("timeouts.request: can't be negative, got %s", c.Timeouts.Request.Duration()))
+	}
+	if c.Timeouts.Probe <= 0 {
+		problems = append(problems, fmt.Sprintf("timeouts.probe: must be positive, got %s", c.Timeouts.Probe.Duration()))
+	}
+
+	problems = append(problems, validateKubeconfig(c.Kubeconfig)...)
+	problems = append(problems, validateAliases(c.Aliases)...)
+	problems = append(problems, validateTags(c.Tags)...)
+	problems = append(problems, validateProtected(c.Protected)...)
+	problems = append(problems, validateHooks(c.Hooks)...)
+	problems = append(problems, validateEnv(c.Env)...)
+	problems = append(problems, validateAudit(c.Audit)...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n  %s", ErrInvalidConfig, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
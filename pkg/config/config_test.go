@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTempConfig points the configuration file at a temporary directory
+func useTempConfig(t *testing.T) (string, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "kontext-config-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	path := filepath.Join(tmpDir, "kontext", "config.yaml")
+
+	originalEnv := os.Getenv(ConfigEnv)
+	_ = os.Setenv(ConfigEnv, path)
+
+	return path, func() {
+		_ = os.Setenv(ConfigEnv, originalEnv)
+		_ = os.RemoveAll(tmpDir)
+	}
+}
+
+// writeConfig writes a configuration file
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func TestGetConfigPath(t *testing.T) {
+	originalConfig := os.Getenv(ConfigEnv)
+	originalXDG := os.Getenv("XDG_CONFIG_HOME")
+	originalHome := os.Getenv("HOME")
+	defer func() {
+		_ = os.Setenv(ConfigEnv, originalConfig)
+		_ = os.Setenv("XDG_CONFIG_HOME", originalXDG)
+		_ = os.Setenv("HOME", originalHome)
+	}()
+
+	_ = os.Setenv(ConfigEnv, "")
+	_ = os.Setenv("XDG_CONFIG_HOME", "")
+	_ = os.Setenv("HOME", "/home/user")
+	if got := GetConfigPath(); got != "/home/user/.config/kontext/config.yaml" {
+		t.Errorf("GetConfigPath() = %v", got)
+	}
+
+	_ = os.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got := GetConfigPath(); got != "/xdg/kontext/config.yaml" {
+		t.Errorf("GetConfigPath() with XDG_CONFIG_HOME = %v", got)
+	}
+
+	_ = os.Setenv(ConfigEnv, "/custom.yaml")
+	if got := GetConfigPath(); got != "/custom.yaml" {
+		t.Errorf("GetConfigPath() with %s = %v", ConfigEnv, got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, cleanup := useTempConfig(t)
+	defer cleanup()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(config, Default()) {
+		t.Errorf("Load() without a file = %+v, want the defaults", config)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path, cleanup := useTempConfig(t)
+	defer cleanup()
+
+	writeConfig(t, path, `color: never
+selector:
+  size: 20
+timeouts:
+  request: 3s
+`)
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if config.Color != "never" || config.Selector.Size != 20 || config.Timeouts.Request.Duration() != 3*time.Second {
+		t.Errorf("Load() = %+v", config)
+	}
+
+	// Settings missing from the file keep their defaults
+	if !config.Selector.CurrentFirst || config.Timeouts.Probe.Duration() != 5*time.Second {
+		t.Errorf("Load() lost defaults: %+v", config)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Unknown key",
+			content: "colour: never\n",
+			want:    "colour",
+		},
+		{
+			name:    "Bad duration",
+			content: "timeouts:\n  probe: 5\n",
+			want:    "duration must be a string",
+		},
+		{
+			name:    "Invalid values are all reported",
+			content: "color: sometimes\nselector:\n  size: 0\n",
+			want:    "color: must be auto, always or never, got 'sometimes'\n  selector.size: must be between 1 and 100, got 0",
+		},
+		{
+			name:    "Negative audit rotation",
+			content: "audit:\n  maxSize: -1\n",
+			want:    "audit.maxSize: can't be negative, got -1",
+		},
+		{
+			name:    "Invalid namespace label",
+			content: "namespaces:\n  labels:\n    team: platform\n    bad key: x\n",
+			want:    "namespaces.labels: invalid label 'bad key=x'",
+		},
+		{
+			name:    "Invalid fragment glob",
+			content: "kubeconfig:\n  glob: '~/.kube/[configs.d/*.yaml'\n",
+			want:    "kubeconfig.glob: invalid glob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, cleanup := useTempConfig(t)
+			defer cleanup()
+			writeConfig(t, path, tt.content)
+
+			_, err := Load()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Load() error = %v, want %v", err, ErrInvalidConfig)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	path, cleanup := useTempConfig(t)
+	defer cleanup()
+	writeConfig(t, path, "selector:\n  size: 20\n")
+
+	for name, value := range map[string]string{
+		"KONTEXT_SELECTOR_SIZE":       "30",
+		"KONTEXT_FALLBACK_NAMESPACES": "default, apps",
+		"KONTEXT_ASCII":               "true",
+		"KONTEXT_KUBECONFIG_GLOB":     "~/.kube/configs.d/*.yaml",
+	} {
+		original := os.Getenv(name)
+		_ = os.Setenv(name, value)
+		defer func(name, original string) { _ = os.Setenv(name, original) }(name, original)
+	}
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if config.Selector.Size != 30 || !config.ASCII || config.Kubeconfig.Glob != "~/.kube/configs.d/*.yaml" {
+		t.Errorf("Load() = %+v, want the environment overrides", config)
+	}
+	if !reflect.DeepEqual(config.Namespaces.Fallback, []string{"default", "apps"}) {
+		t.Errorf("Load() fallback namespaces = %v", config.Namespaces.Fallback)
+	}
+
+	_ = os.Setenv("KONTEXT_SELECTOR_SIZE", "many")
+	if _, err := Load(); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "KONTEXT_SELECTOR_SIZE") {
+		t.Errorf("Load() with an invalid override error = %v", err)
+	}
+}
+
+func TestGetSetSave(t *testing.T) {
+	path, cleanup := useTempConfig(t)
+	defer cleanup()
+
+	config := Default()
+	if err := config.Set("selector.size", "15"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := config.Set("Timeouts.Probe", "2s"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := config.Set("selector.currentFirst", "maybe"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Set() with an invalid bool error = %v, want %v", err, ErrInvalidConfig)
+	}
+	if err := config.Set("selector.height", "5"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Set() with an unknown key error = %v, want %v", err, ErrUnknownKey)
+	}
+	if _, err := config.Get("nope"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Get() with an unknown key error = %v, want %v", err, ErrUnknownKey)
+	}
+
+	if err := config.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	for key, want := range map[string]string{"selector.size": "15", "timeouts.probe": "2s", "color": "auto"} {
+		if got, _ := loaded.Get(key); got != want {
+			t.Errorf("Get(%s) after Save = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	path, cleanup := useTempConfig(t)
+	defer cleanup()
+
+	config := Default()
+	if err := config.SetAlias("p", "arn:aws:eks:eu-west-1:123456789012:cluster/prod"); err != nil {
+		t.Fatalf("SetAlias() error = %v", err)
+	}
+	if err := config.SetAlias("d", "dev"); err != nil {
+		t.Fatalf("SetAlias() error = %v", err)
+	}
+	for _, alias := range []string{"", "my alias", "pr-*", "-p"} {
+		if err := config.SetAlias(alias, "dev"); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("SetAlias(%q) error = %v, want %v", alias, err, ErrInvalidConfig)
+		}
+	}
+
+	if err := config.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, ok := loaded.ResolveAlias("p"); !ok || got != "arn:aws:eks:eu-west-1:123456789012:cluster/prod" {
+		t.Errorf("ResolveAlias(p) = %v, %v", got, ok)
+	}
+	if _, ok := loaded.ResolveAlias("prod"); ok {
+		t.Error("ResolveAlias(prod) found an alias that doesn't exist")
+	}
+	if got := loaded.AliasNames(); !reflect.DeepEqual(got, []string{"d", "p"}) {
+		t.Errorf("AliasNames() = %v", got)
+	}
+	if got := loaded.AliasesFor("dev"); !reflect.DeepEqual(got, []string{"d"}) {
+		t.Errorf("AliasesFor(dev) = %v", got)
+	}
+	if got := loaded.AliasesFor("staging"); len(got) != 0 {
+		t.Errorf("AliasesFor(staging) = %v, want none", got)
+	}
+
+	if err := loaded.RemoveAlias("d"); err != nil {
+		t.Errorf("RemoveAlias(d) error = %v", err)
+	}
+	if err := loaded.RemoveAlias("d"); !errors.Is(err, ErrAliasNotFound) {
+		t.Errorf("RemoveAlias(d) twice error = %v, want %v", err, ErrAliasNotFound)
+	}
+
+	writeConfig(t, path, "aliases:\n  p: \"\"\n")
+	if _, err := Load(); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "aliases.p") {
+		t.Errorf("Load() with an empty alias target error = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// EnvVars are environment variables the shell integration exports for matching contexts
+// Entries without contexts or tags apply to every context. When several
+// entries set the same variable, the last one wins.
+type EnvVars struct {
+	// Contexts lists context names or shell patterns
+	Contexts []string `json:"contexts,omitempty"`
+	// Tags lists tag expressions such as env=prod
+	Tags []string `json:"tags,omitempty"`
+	// Vars maps variable names to their literal values
+	Vars map[string]string `json:"vars"`
+}
+
+// envName matches valid environment variable names
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnv are variables kontext manages itself
+// KUBECONFIG and the KONTEXT_PIN variables belong to .kontext files, and
+// KONTEXT_SESSION identifies the shell running the integration.
+var reservedEnv = map[string]bool{
+	"KONTEXT_ENV_VARS":       true,
+	"KUBECONFIG":             true,
+	"KONTEXT_PIN":            true,
+	"KONTEXT_PIN_KUBECONFIG": true,
+	"KONTEXT_PIN_REPORTED":   true,
+	"KONTEXT_SESSION":        true,
+}
+
+// EnvFor returns the environment variables of a context
+func (c *Config) EnvFor(contextName string) map[string]string {
+	vars := make(map[string]string)
+	for _, entry := range c.Env {
+		global := len(entry.Contexts) == 0 && len(entry.Tags) == 0
+		if !global && !c.matchesContext(contextName, entry.Contexts, entry.Tags) {
+			continue
+		}
+		for name, value := range entry.Vars {
+			vars[name] = value
+		}
+	}
+	return vars
+}
+
+// validateEnv checks the selectors and variable names of every entry
+func validateEnv(entries []EnvVars) []string {
+	var problems []string
+	for i, entry := range entries {
+		key := fmt.Sprintf("env[%d]", i)
+		problems = append(problems, validateContextMatch(key, entry.Contexts, entry.Tags)...)
+		if len(entry.Vars) == 0 {
+			problems = append(problems, key+".vars: must set at least one variable")
+		}
+		for _, name := range sortedKeys(entry.Vars) {
+			switch {
+			case !envName.MatchString(name):
+				problems = append(problems, fmt.Sprintf("%s.vars: '%s' is not a valid variable name", key, name))
+			case reservedEnv[name]:
+				problems = append(problems, fmt.Sprintf("%s.vars: '%s' is managed by kontext", key, name))
+			}
+		}
+	}
+	return problems
+}
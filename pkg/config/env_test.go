@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnvFor(t *testing.T) {
+	config := Default()
+	config.Tags = map[string]map[string]string{"payments": {"cloud": "aws"}}
+	config.Env = []EnvVars{
+		{Vars: map[string]string{"VAULT_ADDR": "https://vault.dev"}},
+		{Tags: []string{"cloud=aws"}, Vars: map[string]string{"AWS_PROFILE": "payments"}},
+		{Contexts: []string{"pay*"}, Vars: map[string]string{"VAULT_ADDR": "https://vault.prod"}},
+	}
+
+	want := map[string]string{"VAULT_ADDR": "https://vault.prod", "AWS_PROFILE": "payments"}
+	if got := config.EnvFor("payments"); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvFor(payments) = %v, want %v", got, want)
+	}
+	if got := config.EnvFor("dev"); !reflect.DeepEqual(got, map[string]string{"VAULT_ADDR": "https://vault.dev"}) {
+		t.Errorf("EnvFor(dev) = %v", got)
+	}
+
+	config.Env = []EnvVars{{Vars: map[string]string{"1BAD": "x", "KONTEXT_ENV_VARS": "y", "KUBECONFIG": "z", "KONTEXT_PIN": "p"}}, {Contexts: []string{"dev"}}}
+	err := config.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidConfig)
+	}
+	for _, problem := range []string{"'1BAD' is not a valid variable name", "'KONTEXT_ENV_VARS' is managed by kontext", "'KUBECONFIG' is managed by kontext", "'KONTEXT_PIN' is managed by kontext", "env[1].vars"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Validate() error = %v, want it to mention %q", err, problem)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hook phases
+const (
+	// PreHook runs before a change and can veto it by failing
+	PreHook = "pre"
+	// PostHook runs after a change; failures are only reported
+	PostHook = "post"
+)
+
+// Hooks are commands run before and after context and namespace changes
+type Hooks struct {
+	// Timeout limits each hook unless the hook sets its own
+	Timeout Duration `json:"timeout"`
+	// Pre hooks run before a change
+	Pre []Hook `json:"pre,omitempty"`
+	// Post hooks run after a change
+	Post []Hook `json:"post,omitempty"`
+}
+
+// Hook is a shell command run around context and namespace changes
+// A hook without contexts or tags runs for every context; otherwise it only
+// runs when the new context matches one of them.
+type Hook struct {
+	// Run is the command, run with sh -c
+	Run string `json:"run"`
+	// Contexts lists context names or shell patterns
+	Contexts []string `json:"contexts,omitempty"`
+	// Tags lists tag expressions such as env=prod
+	Tags []string `json:"tags,omitempty"`
+	// Timeout overrides hooks.timeout for this hook
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// HooksFor returns the hooks of a phase that apply to a context, in order
+func (c *Config) HooksFor(phase, contextName string) []Hook {
+	hooks := c.Hooks.Pre
+	if phase == PostHook {
+		hooks = c.Hooks.Post
+	}
+
+	var matched []Hook
+	for _, hook := range hooks {
+		global := len(hook.Contexts) == 0 && len(hook.Tags) == 0
+		if global || c.matchesContext(contextName, hook.Contexts, hook.Tags) {
+			matched = append(matched, hook)
+		}
+	}
+	return matched
+}
+
+// HookTimeout returns how long a hook may run
+func (c *Config) HookTimeout(hook Hook) Duration {
+	if hook.Timeout > 0 {
+		return hook.Timeout
+	}
+	return c.Hooks.Timeout
+}
+
+// validateHooks checks every hook's command, selectors and timeout
+func validateHooks(h Hooks) []string {
+	var problems []string
+	if h.Timeout <= 0 {
+		problems = append(problems, fmt.Sprintf("hooks.timeout: must be positive, got %s", h.Timeout.Duration()))
+	}
+	for _, phase := range []string{PreHook, PostHook} {
+		hooks := h.Pre
+		if phase == PostHook {
+			hooks = h.Post
+		}
+		for i, hook := range hooks {
+			key := fmt.Sprintf("hooks.%s[%d]", phase, i)
+			if strings.TrimSpace(hook.Run) == "" {
+				problems = append(problems, key+".run: must be a command")
+			}
+			if hook.Timeout < 0 {
+				problems = append(problems, fmt.Sprintf("%s.timeout: can't be negative, got %s", key, hook.Timeout.Duration()))
+			}
+			problems = append(problems, validateContextMatch(key, hook.Contexts, hook.Tags)...)
+		}
+	}
+	return problems
+}
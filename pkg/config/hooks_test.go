@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHooksFor(t *testing.T) {
+	config := Default()
+	config.Tags = map[string]map[string]string{"payments": {"cloud": "aws"}}
+	config.Hooks.Pre = []Hook{
+		{Run: "vpn-check"},
+		{Run: "aws sso login", Tags: []string{"cloud=aws"}},
+		{Run: "gcloud config set project x", Contexts: []string{"gke-*"}, Timeout: Duration(time.Minute)},
+	}
+
+	runs := func(hooks []Hook) []string {
+		var commands []string
+		for _, hook := range hooks {
+			commands = append(commands, hook.Run)
+		}
+		return commands
+	}
+
+	if got := runs(config.HooksFor(PreHook, "payments")); !reflect.DeepEqual(got, []string{"vpn-check", "aws sso login"}) {
+		t.Errorf("HooksFor(pre, payments) = %v", got)
+	}
+	gke := config.HooksFor(PreHook, "gke-eu")
+	if got := runs(gke); !reflect.DeepEqual(got, []string{"vpn-check", "gcloud config set project x"}) {
+		t.Errorf("HooksFor(pre, gke-eu) = %v", got)
+	}
+	if got := config.HookTimeout(gke[0]); got.Duration() != 30*time.Second {
+		t.Errorf("HookTimeout() = %v, want the default", got.Duration())
+	}
+	if got := config.HookTimeout(gke[1]); got.Duration() != time.Minute {
+		t.Errorf("HookTimeout() = %v, want the hook's own timeout", got.Duration())
+	}
+	if got := config.HooksFor(PostHook, "payments"); len(got) != 0 {
+		t.Errorf("HooksFor(post, payments) = %v", got)
+	}
+
+	config.Hooks.Post = []Hook{{Run: " ", Contexts: []string{"[x"}}}
+	err := config.Validate()
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "hooks.post[0].run") || !strings.Contains(err.Error(), "hooks.post[0].contexts") {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Kubeconfig configures where kontext reads and writes the kubeconfig
+type Kubeconfig struct {
+	// Glob enables fragment mode: every matching file is loaded and merged (empty reads KUBECONFIG)
+	Glob string `json:"glob"`
+	// Overlay is the file storing the current context in fragment mode
+	Overlay string `json:"overlay"`
+}
+
+// validateKubeconfig checks the fragment glob
+func validateKubeconfig(k Kubeconfig) []string {
+	if _, err := filepath.Match(k.Glob, ""); err != nil {
+		return []string{fmt.Sprintf("kubeconfig.glob: invalid glob '%s': %v", k.Glob, err)}
+	}
+	return nil
+}
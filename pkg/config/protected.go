@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+)
+
+// ErrProtectedContext is returned when changing a protected context without --force
+var ErrProtectedContext = errors.New("context is protected")
+
+// Protected marks contexts that need extra care, such as production clusters
+type Protected struct {
+	// Contexts lists context names or shell patterns (prod-*, *:cluster/prod)
+	Contexts []string `json:"contexts,omitempty"`
+	// Tags lists tag expressions such as env=prod; a context matching any of them is protected
+	Tags []string `json:"tags,omitempty"`
+	// Confirm is how switching to a protected context is confirmed: name (type it) or yes
+	Confirm string `json:"confirm"`
+}
+
+// IsProtected reports whether a context matches a protected pattern or tag expression
+func (c *Config) IsProtected(contextName string) bool {
+	return c.matchesContext(contextName, c.Protected.Contexts, c.Protected.Tags)
+}
+
+// ProtectedContexts returns the protected contexts among contextNames
+func (c *Config) ProtectedContexts(contextNames []string) []string {
+	var protected []string
+	for _, name := range contextNames {
+		if c.IsProtected(name) {
+			protected = append(protected, name)
+		}
+	}
+	return protected
+}
+
+// matchesContext reports whether a context matches any of the patterns or tag expressions
+func (c *Config) matchesContext(contextName string, patterns, tagExprs []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := kubeconfig.MatchPattern(contextName, pattern); matched {
+			return true
+		}
+	}
+	for _, expr := range tagExprs {
+		selector, err := ParseTagSelector(expr)
+		if err == nil && !selector.Empty() && selector.Matches(c.Tags[contextName]) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateContextMatch checks context patterns and tag expressions found under key
+func validateContextMatch(key string, patterns, tagExprs []string) []string {
+	var problems []string
+	for _, pattern := range patterns {
+		if pattern == "" {
+			problems = append(problems, key+".contexts: patterns can't be empty")
+		} else if _, err := kubeconfig.MatchPattern("", pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.contexts: %v", key, err))
+		}
+	}
+	for _, expr := range tagExprs {
+		if selector, err := ParseTagSelector(expr); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.tags: %v", key, err))
+		} else if selector.Empty() {
+			problems = append(problems, key+".tags: tag expressions can't be empty")
+		}
+	}
+	return problems
+}
+
+// validateProtected checks the protected patterns and tag expressions
+func validateProtected(p Protected) []string {
+	problems := validateContextMatch("protected", p.Contexts, p.Tags)
+	switch p.Confirm {
+	case "name", "yes":
+	default:
+		problems = append(problems, fmt.Sprintf("protected.confirm: must be name or yes, got '%s'", p.Confirm))
+	}
+	return problems
+}
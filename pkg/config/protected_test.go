@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsProtected(t *testing.T) {
+	config := Default()
+	config.Protected.Contexts = []string{"prod-*", "arn:aws:eks:*:cluster/live"}
+	config.Protected.Tags = []string{"env=prod", "tier=critical,region=eu"}
+	config.Tags = map[string]map[string]string{
+		"payments":     {"env": "prod"},
+		"analytics":    {"tier": "critical", "region": "us"},
+		"analytics-eu": {"tier": "critical", "region": "eu"},
+	}
+
+	contexts := []string{
+		"dev",
+		"prod-eu",
+		"arn:aws:eks:eu-west-1:123456789012:cluster/live",
+		"payments",
+		"analytics",
+		"analytics-eu",
+	}
+	want := []string{
+		"prod-eu",
+		"arn:aws:eks:eu-west-1:123456789012:cluster/live",
+		"payments",
+		"analytics-eu",
+	}
+	if got := config.ProtectedContexts(contexts); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProtectedContexts() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateProtected(t *testing.T) {
+	config := Default()
+	config.Protected = Protected{
+		Contexts: []string{"prod-[eu"},
+		Tags:     []string{"env=prod=eu"},
+		Confirm:  "always",
+	}
+
+	err := config.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidConfig)
+	}
+	for _, want := range []string{"protected.contexts", "protected.tags", "protected.confirm"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error = %v, want it to mention %s", err, want)
+		}
+	}
+}
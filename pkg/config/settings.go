@@ -0,0 +1,295 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describes a single configuration key
+type setting struct {
+	key         string
+	env         string
+	description string
+	get         func(c *Config) string
+	set         func(c *Config, value string) error
+}
+
+// settings lists every configuration key, in the order they are documented
+var settings = []setting{
+	{
+		key:         "color",
+		env:         "KONTEXT_COLOR",
+		description: "When to color output: auto, always or never",
+		get:         func(c *Config) string { return c.Color },
+		set: func(c *Config, value string) error {
+			c.Color = strings.ToLower(value)
+			return nil
+		},
+	},
+	{
+		key:         "ascii",
+		env:         "KONTEXT_ASCII",
+		description: "Use plain ASCII symbols instead of unicode",
+		get:         func(c *Config) string { return strconv.FormatBool(c.ASCII) },
+		set: func(c *Config, value string) error {
+			return parseBool(value, &c.ASCII)
+		},
+	},
+	{
+		key:         "selector.size",
+		env:         "KONTEXT_SELECTOR_SIZE",
+		description: "Number of items shown at once in interactive selectors",
+		get:         func(c *Config) string { return strconv.Itoa(c.Selector.Size) },
+		set: func(c *Config, value string) error {
+			return parseInt(value, &c.Selector.Size)
+		},
+	},
+	{
+		key:         "selector.currentFirst",
+		env:         "KONTEXT_SELECTOR_CURRENT_FIRST",
+		description: "List the current context or namespace first instead of alphabetically",
+		get:         func(c *Config) string { return strconv.FormatBool(c.Selector.CurrentFirst) },
+		set: func(c *Config, value string) error {
+			return parseBool(value, &c.Selector.CurrentFirst)
+		},
+	},
+	{
+		key:         "namespaces.fallback",
+		env:         "KONTEXT_FALLBACK_NAMESPACES",
+		description: "Comma-separated namespaces offered when a cluster can't be queried",
+		get:         func(c *Config) string { return strings.Join(c.Namespaces.Fallback, ",") },
+		set: func(c *Config, value string) error {
+			c.Namespaces.Fallback = parseList(value)
+			return nil
+		},
+	},
+	{
+		key:         "namespaces.keep",
+		env:         "KONTEXT_KEEP_NAMESPACE",
+		description: "Stay in the current namespace when switching to a context where it exists",
+		get:         func(c *Config) string { return strconv.FormatBool(c.Namespaces.Keep) },
+		set: func(c *Config, value string) error {
+			return parseBool(value, &c.Namespaces.Keep)
+		},
+	},
+	{
+		key:         "namespaces.restore",
+		env:         "KONTEXT_RESTORE_NAMESPACE",
+		description: "Switch to the namespace last chosen in a context, even if the kubeconfig lost it",
+		get:         func(c *Config) string { return strconv.FormatBool(c.Namespaces.Restore) },
+		set: func(c *Config, value string) error {
+			return parseBool(value, &c.Namespaces.Restore)
+		},
+	},
+	{
+		key:         "timeouts.request",
+		env:         "KONTEXT_REQUEST_TIMEOUT",
+		description: "Timeout of cluster API calls such as listing namespaces (0 keeps the client-go default)",
+		get:         func(c *Config) string { return c.Timeouts.Request.Duration().String() },
+		set: func(c *Config, value string) error {
+			return parseDuration(value, &c.Timeouts.Request)
+		},
+	},
+	{
+		key:         "timeouts.probe",
+		env:         "KONTEXT_PROBE_TIMEOUT",
+		description: "Timeout of each cluster probe of `kontext prune --probe`",
+		get:         func(c *Config) string { return c.Timeouts.Probe.Duration().String() },
+		set: func(c *Config, value string) error {
+			return parseDuration(value, &c.Timeouts.Probe)
+		},
+	},
+	{
+		key:         "kubeconfig.glob",
+		env:         "KONTEXT_KUBECONFIG_GLOB",
+		description: "Glob of kubeconfig fragments to load and merge (empty reads KUBECONFIG)",
+		get:         func(c *Config) string { return c.Kubeconfig.Glob },
+		set: func(c *Config, value string) error {
+			c.Kubeconfig.Glob = value
+			return nil
+		},
+	},
+	{
+		key:         "kubeconfig.overlay",
+		env:         "KONTEXT_OVERLAY",
+		description: "File storing the current context in fragment mode",
+		get:         func(c *Config) string { return c.Kubeconfig.Overlay },
+		set: func(c *Config, value string) error {
+			c.Kubeconfig.Overlay = value
+			return nil
+		},
+	},
+	{
+		key:         "protected.contexts",
+		env:         "KONTEXT_PROTECTED_CONTEXTS",
+		description: "Comma-separated context names or patterns that are protected",
+		get:         func(c *Config) string { return strings.Join(c.Protected.Contexts, ",") },
+		set: func(c *Config, value string) error {
+			c.Protected.Contexts = parseList(value)
+			return nil
+		},
+	},
+	{
+		key:         "protected.confirm",
+		env:         "KONTEXT_PROTECTED_CONFIRM",
+		description: "How switching to a protected context is confirmed: name or yes",
+		get:         func(c *Config) string { return c.Protected.Confirm },
+		set: func(c *Config, value string) error {
+			c.Protected.Confirm = strings.ToLower(value)
+			return nil
+		},
+	},
+	{
+		key:         "hooks.timeout",
+		env:         "KONTEXT_HOOK_TIMEOUT",
+		description: "Timeout of each pre and post switch hook",
+		get:         func(c *Config) string { return c.Hooks.Timeout.Duration().String() },
+		set: func(c *Config, value string) error {
+			return parseDuration(value, &c.Hooks.Timeout)
+		},
+	},
+	{
+		key:         "audit.enabled",
+		env:         "KONTEXT_AUDIT",
+		description: "Record every kubeconfig change in the audit log",
+		get:         func(c *Config) string { return strconv.FormatBool(c.Audit.Enabled) },
+		set: func(c *Config, value string) error {
+			return parseBool(value, &c.Audit.Enabled)
+		},
+	},
+	{
+		key:         "audit.path",
+		env:         "KONTEXT_AUDIT_PATH",
+		description: "Audit log file (empty for audit.jsonl in the state directory)",
+		get:         func(c *Config) string { return c.Audit.Path },
+		set: func(c *Config, value string) error {
+			c.Audit.Path = value
+			return nil
+		},
+	},
+	{
+		key:         "audit.maxSize",
+		env:         "KONTEXT_AUDIT_MAX_SIZE",
+		description: "Size in megabytes after which the audit log is rotated (0 never rotates)",
+		get:         func(c *Config) string { return strconv.Itoa(c.Audit.MaxSize) },
+		set: func(c *Config, value string) error {
+			return parseInt(value, &c.Audit.MaxSize)
+		},
+	},
+	{
+		key:         "audit.maxFiles",
+		env:         "KONTEXT_AUDIT_MAX_FILES",
+		description: "Number of rotated audit logs kept",
+		get:         func(c *Config) string { return strconv.Itoa(c.Audit.MaxFiles) },
+		set: func(c *Config, value string) error {
+			return parseInt(value, &c.Audit.MaxFiles)
+		},
+	},
+}
+
+// Key describes a configuration key for help and completion
+type Key struct {
+	Name        string
+	Env         string
+	Description string
+}
+
+// Keys returns every configuration key
+func Keys() []Key {
+	keys := make([]Key, 0, len(settings))
+	for _, s := range settings {
+		keys = append(keys, Key{Name: s.key, Env: s.env, Description: s.description})
+	}
+	return keys
+}
+
+// lookup returns the setting for a key
+// Keys are matched case-insensitively.
+func lookup(key string) (setting, error) {
+	for _, s := range settings {
+		if strings.EqualFold(s.key, key) {
+			return s, nil
+		}
+	}
+	return setting{}, fmt.Errorf("%w: '%s'", ErrUnknownKey, key)
+}
+
+// Get returns the value of a key as a string
+func (c *Config) Get(key string) (string, error) {
+	s, err := lookup(key)
+	if err != nil {
+		return "", err
+	}
+	return s.get(c), nil
+}
+
+// Set changes the value of a key from a string
+// The value is parsed but not validated; call Validate before saving.
+func (c *Config) Set(key, value string) error {
+	s, err := lookup(key)
+	if err != nil {
+		return err
+	}
+	if err := s.set(c, value); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, s.key, err)
+	}
+	return nil
+}
+
+// ApplyEnv overrides settings from their environment variables
+func (c *Config) ApplyEnv() error {
+	for _, s := range settings {
+		value, ok := os.LookupEnv(s.env)
+		if !ok || value == "" {
+			continue
+		}
+		if err := s.set(c, value); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, s.env, err)
+		}
+	}
+	return nil
+}
+
+// parseBool parses a boolean setting
+func parseBool(value string, dst *bool) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("must be true or false, got '%s'", value)
+	}
+	*dst = parsed
+	return nil
+}
+
+// parseInt parses a number setting
+func parseInt(value string, dst *int) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("must be a number, got '%s'", value)
+	}
+	*dst = parsed
+	return nil
+}
+
+// parseList parses a comma-separated list setting, dropping empty items
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseDuration parses a duration setting such as "5s"
+func parseDuration(value string, dst *Duration) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("must be a duration such as 5s or 1m, got '%s'", value)
+	}
+	*dst = Duration(parsed)
+	return nil
+}
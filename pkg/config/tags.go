@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseTags parses tags given as key=value or as a bare key
+func ParseTags(args []string) (map[string]string, error) {
+	tags := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, "=")
+		if problem := validateTag(key, value); problem != "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConfig, problem)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// TagsFor returns the tags of a context
+// The returned map must not be modified.
+func (c *Config) TagsFor(contextName string) map[string]string {
+	return c.Tags[contextName]
+}
+
+// SetTags adds tags to a context, replacing the values of existing keys
+func (c *Config) SetTags(contextName string, tags map[string]string) {
+	if c.Tags == nil {
+		c.Tags = make(map[string]map[string]string)
+	}
+	if c.Tags[contextName] == nil {
+		c.Tags[contextName] = make(map[string]string, len(tags))
+	}
+	for key, value := range tags {
+		c.Tags[contextName][key] = value
+	}
+}
+
+// RemoveTags removes tag keys from a context
+// Keys the context doesn't have are ignored.
+func (c *Config) RemoveTags(contextName string, keys []string) {
+	for _, key := range keys {
+		delete(c.Tags[contextName], key)
+	}
+	if len(c.Tags[contextName]) == 0 {
+		delete(c.Tags, contextName)
+	}
+}
+
+// FormatTags returns tags as a sorted, comma-separated list of key=value
+func FormatTags(tags map[string]string) string {
+	terms := make([]string, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		if tags[key] == "" {
+			terms = append(terms, key)
+		} else {
+			terms = append(terms, key+"="+tags[key])
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// tagTerm is a single condition of a TagSelector
+type tagTerm struct {
+	key    string
+	value  string
+	op     string // "=", "!=", "exists" or "!exists"
+	source string
+}
+
+// TagSelector selects contexts by their tags
+type TagSelector []tagTerm
+
+// ParseTagSelector parses a comma-separated tag expression
+//
+// Every term must match: key=value, key!=value, key (the context has the
+// tag) or !key (it doesn't). An empty expression matches every context.
+func ParseTagSelector(expr string) (TagSelector, error) {
+	var selector TagSelector
+	for _, source := range strings.Split(expr, ",") {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+
+		term := tagTerm{source: source}
+		switch {
+		case strings.Contains(source, "!="):
+			term.key, term.value, _ = strings.Cut(source, "!=")
+			term.op = "!="
+		case strings.Contains(source, "="):
+			term.key, term.value, _ = strings.Cut(source, "=")
+			term.op = "="
+		case strings.HasPrefix(source, "!"):
+			term.key = strings.TrimPrefix(source, "!")
+			term.op = "!exists"
+		default:
+			term.key = source
+			term.op = "exists"
+		}
+		term.key = strings.TrimSpace(term.key)
+		term.value = strings.TrimSpace(term.value)
+
+		if problem := validateTag(term.key, term.value); problem != "" {
+			return nil, fmt.Errorf("invalid tag expression '%s': %s", source, problem)
+		}
+		selector = append(selector, term)
+	}
+	return selector, nil
+}
+
+// Empty reports whether the selector matches every context
+func (s TagSelector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches reports whether tags satisfy every term of the selector
+func (s TagSelector) Matches(tags map[string]string) bool {
+	for _, term := range s {
+		value, exists := tags[term.key]
+		switch term.op {
+		case "=":
+			if !exists || value != term.value {
+				return false
+			}
+		case "!=":
+			if exists && value == term.value {
+				return false
+			}
+		case "exists":
+			if !exists {
+				return false
+			}
+		case "!exists":
+			if exists {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the selector as it was written
+func (s TagSelector) String() string {
+	terms := make([]string, len(s))
+	for i, term := range s {
+		terms[i] = term.source
+	}
+	return strings.Join(terms, ",")
+}
+
+// FilterContexts returns the context names whose tags match the selector
+func (c *Config) FilterContexts(contextNames []string, selector TagSelector) []string {
+	if selector.Empty() {
+		return contextNames
+	}
+	var matched []string
+	for _, name := range contextNames {
+		if selector.Matches(c.Tags[name]) {
+			matched = append(matched, name)
+		}
+	}
+	return matched
+}
+
+// GroupContexts returns the value of a tag key for each context, used to group them
+// Contexts without the tag are not in the result.
+func (c *Config) GroupContexts(contextNames []string, key string) map[string]string {
+	groups := make(map[string]string)
+	for _, name := range contextNames {
+		if value, exists := c.Tags[name][key]; exists {
+			groups[name] = key + "=" + value
+		}
+	}
+	return groups
+}
+
+// validateTag checks a tag key and value, returning a problem or ""
+func validateTag(key, value string) string {
+	switch {
+	case key == "":
+		return "tag keys can't be empty"
+	case strings.ContainsAny(key, " \t\n,=!"):
+		return fmt.Sprintf("tag key '%s' can't contain spaces or ,=!", key)
+	case strings.ContainsAny(value, "\n,="):
+		return fmt.Sprintf("tag value '%s' can't contain , or =", value)
+	}
+	return ""
+}
+
+// validateTags checks every tag of every context
+func validateTags(tags map[string]map[string]string) []string {
+	var problems []string
+
+	contextNames := make([]string, 0, len(tags))
+	for name := range tags {
+		contextNames = append(contextNames, name)
+	}
+	sort.Strings(contextNames)
+
+	for _, name := range contextNames {
+		for _, key := range sortedKeys(tags[name]) {
+			if problem := validateTag(key, tags[name][key]); problem != "" {
+				problems = append(problems, fmt.Sprintf("tags.%s: %s", name, problem))
+			}
+		}
+	}
+	return problems
+}
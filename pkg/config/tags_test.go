@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"env=prod", "region=eu", "critical"})
+	if err != nil {
+		t.Fatalf("ParseTags() error = %v", err)
+	}
+	want := map[string]string{"env": "prod", "region": "eu", "critical": ""}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("ParseTags() = %v, want %v", tags, want)
+	}
+	if got := FormatTags(tags); got != "critical,env=prod,region=eu" {
+		t.Errorf("FormatTags() = %v", got)
+	}
+
+	for _, arg := range []string{"=prod", "env=a,b", "bad key=x", "!env"} {
+		if _, err := ParseTags([]string{arg}); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("ParseTags(%q) error = %v, want %v", arg, err, ErrInvalidConfig)
+		}
+	}
+}
+
+func TestTagSelector(t *testing.T) {
+	prodEU := map[string]string{"env": "prod", "region": "eu", "critical": ""}
+	devUS := map[string]string{"env": "dev", "region": "us"}
+
+	tests := []struct {
+		expr   string
+		prodEU bool
+		devUS  bool
+		none   bool
+	}{
+		{expr: "", prodEU: true, devUS: true, none: true},
+		{expr: "env=prod", prodEU: true},
+		{expr: "env=prod,region=eu", prodEU: true},
+		{expr: "env=prod, region=us"},
+		{expr: "env!=prod", devUS: true, none: true},
+		{expr: "critical", prodEU: true},
+		{expr: "!critical", devUS: true, none: true},
+		{expr: "region,!critical", devUS: true},
+	}
+
+	for _, tt := range tests {
+		selector, err := ParseTagSelector(tt.expr)
+		if err != nil {
+			t.Errorf("ParseTagSelector(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got := selector.Matches(prodEU); got != tt.prodEU {
+			t.Errorf("%q matches prod-eu = %v, want %v", tt.expr, got, tt.prodEU)
+		}
+		if got := selector.Matches(devUS); got != tt.devUS {
+			t.Errorf("%q matches dev-us = %v, want %v", tt.expr, got, tt.devUS)
+		}
+		if got := selector.Matches(nil); got != tt.none {
+			t.Errorf("%q matches untagged = %v, want %v", tt.expr, got, tt.none)
+		}
+	}
+
+	for _, expr := range []string{"=prod", "env=a=b", "!"} {
+		if _, err := ParseTagSelector(expr); err == nil {
+			t.Errorf("ParseTagSelector(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestTagsOnConfig(t *testing.T) {
+	config := Default()
+	config.SetTags("prod-eu", map[string]string{"env": "prod", "region": "eu"})
+	config.SetTags("dev-us", map[string]string{"env": "dev", "region": "us"})
+	config.SetTags("prod-eu", map[string]string{"region": "eu-west"})
+
+	if got := config.TagsFor("prod-eu"); !reflect.DeepEqual(got, map[string]string{"env": "prod", "region": "eu-west"}) {
+		t.Errorf("TagsFor(prod-eu) = %v", got)
+	}
+
+	selector, _ := ParseTagSelector("env=prod")
+	names := []string{"dev-us", "prod-eu", "untagged"}
+	if got := config.FilterContexts(names, selector); !reflect.DeepEqual(got, []string{"prod-eu"}) {
+		t.Errorf("FilterContexts(env=prod) = %v", got)
+	}
+	if got := config.GroupContexts(names, "env"); !reflect.DeepEqual(got, map[string]string{"dev-us": "env=dev", "prod-eu": "env=prod"}) {
+		t.Errorf("GroupContexts(env) = %v", got)
+	}
+
+	config.RemoveTags("dev-us", []string{"env", "region", "missing"})
+	if _, exists := config.Tags["dev-us"]; exists {
+		t.Errorf("RemoveTags() left an empty tag set: %v", config.Tags)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	config.Tags["prod-eu"]["bad key"] = "x"
+	if err := config.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Validate() with an invalid tag error = %v, want %v", err, ErrInvalidConfig)
+	}
+}
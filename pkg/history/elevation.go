@@ -0,0 +1,78 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Elevation is a time-boxed switch to a context, such as production during an incident
+// Once it expires, kontext switches back to the fallback context and namespace.
+type Elevation struct {
+	Context           string    `json:"context"`
+	Expires           time.Time `json:"expires"`
+	FallbackContext   string    `json:"fallbackContext"`
+	FallbackNamespace string    `json:"fallbackNamespace,omitempty"`
+}
+
+// Expired reports whether the elevation has expired at now
+func (e *Elevation) Expired(now time.Time) bool {
+	return !now.Before(e.Expires)
+}
+
+// Remaining returns the time left before the elevation expires at now, never negative
+func (e *Elevation) Remaining(now time.Time) time.Duration {
+	return max(e.Expires.Sub(now), 0)
+}
+
+// elevationPath returns the path of the active elevation file
+func elevationPath() string {
+	return filepath.Join(GetStateDir(), "elevation.json")
+}
+
+// LoadElevation returns the active elevation, or nil if there is none
+func LoadElevation() (*Elevation, error) {
+	data, err := os.ReadFile(elevationPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading elevation: %w", err)
+	}
+
+	var elevation Elevation
+	if err := json.Unmarshal(data, &elevation); err != nil {
+		return nil, fmt.Errorf("error parsing elevation %s: %w", elevationPath(), err)
+	}
+	return &elevation, nil
+}
+
+// SaveElevation stores the active elevation, replacing any previous one
+func SaveElevation(elevation Elevation) error {
+	elevation.Expires = elevation.Expires.UTC()
+
+	data, err := json.MarshalIndent(elevation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding elevation: %w", err)
+	}
+
+	if err := os.MkdirAll(GetStateDir(), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+	if err := os.WriteFile(elevationPath(), data, 0o600); err != nil {
+		return fmt.Errorf("error writing elevation: %w", err)
+	}
+	return nil
+}
+
+// ClearElevation removes the active elevation
+// Clearing when there is none is not an error.
+func ClearElevation() error {
+	if err := os.Remove(elevationPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing elevation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Package history records how kontext is used over time
+//
+// This package stores kontext's own state, separate from the kubeconfig:
+// - A switch history of every context and namespace change
+// - Probe results recording when each context's cluster was last reachable
+// - The active time-boxed switch and where to go back when it expires
+// - The namespace last chosen in each context
+//
+// State lives in $XDG_STATE_HOME/kontext (~/.local/state/kontext by default).
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StateDirEnv overrides the directory where kontext stores its state
+const StateDirEnv = "KONTEXT_STATE_DIR"
+
+// Event is a single context or namespace switch
+type Event struct {
+	Time              time.Time `json:"time"`
+	Context           string    `json:"context"`
+	Namespace         string    `json:"namespace,omitempty"`
+	PreviousContext   string    `json:"previousContext,omitempty"`
+	PreviousNamespace string    `json:"previousNamespace,omitempty"`
+}
+
+// Probe is the reachability record of a context's cluster
+type Probe struct {
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	LastFailure time.Time `json:"lastFailure,omitzero"`
+	// FailingSince is the time of the first failure after the last success
+	// It is zero while the cluster is reachable.
+	FailingSince time.Time `json:"failingSince,omitzero"`
+	LastError    string    `json:"lastError,omitempty"`
+}
+
+// GetStateDir returns the directory where kontext stores its state
+//
+// It checks KONTEXT_STATE_DIR first, then $XDG_STATE_HOME/kontext, and falls
+// back to ~/.local/state/kontext.
+func GetStateDir() string {
+	if dir := os.Getenv(StateDirEnv); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "kontext")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "state", "kontext")
+}
+
+// switchesPath returns the path of the switch history file
+func switchesPath() string {
+	return filepath.Join(GetStateDir(), "switches.jsonl")
+}
+
+// probesPath returns the path of the probe results file
+func probesPath() string {
+	return filepath.Join(GetStateDir(), "probes.json")
+}
+
+// RecordSwitch appends a switch event to the history
+// A zero event time is replaced with the current time.
+func RecordSwitch(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Time = event.Time.UTC()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding switch event: %w", err)
+	}
+
+	if err := os.MkdirAll(GetStateDir(), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+
+	file, err := os.OpenFile(switchesPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening switch history: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing switch history: %w", err)
+	}
+	return nil
+}
+
+// LoadSwitches returns all recorded switch events, oldest first
+// Lines that cannot be parsed are skipped.
+func LoadSwitches() ([]Event, error) {
+	file, err := os.Open(switchesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening switch history: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading switch history: %w", err)
+	}
+
+	return events, nil
+}
+
+// LastUsed returns the time each context was last switched to
+func LastUsed(events []Event) map[string]time.Time {
+	lastUsed := make(map[string]time.Time)
+	for _, event := range events {
+		if event.Time.After(lastUsed[event.Context]) {
+			lastUsed[event.Context] = event.Time
+		}
+	}
+	return lastUsed
+}
+
+// LoadProbes returns the probe records of all contexts
+func LoadProbes() (map[string]Probe, error) {
+	probes := make(map[string]Probe)
+
+	data, err := os.ReadFile(probesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return probes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading probe history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &probes); err != nil {
+		return nil, fmt.Errorf("error parsing probe history %s: %w", probesPath(), err)
+	}
+	return probes, nil
+}
+
+// RecordProbe records whether a context's cluster was reachable
+// A nil probeErr records a success.
+func RecordProbe(contextName string, probeErr error, at time.Time) error {
+	probes, err := LoadProbes()
+	if err != nil {
+		return err
+	}
+
+	at = at.UTC()
+	probe := probes[contextName]
+	if probeErr == nil {
+		probe.LastSuccess = at
+		probe.FailingSince = time.Time{}
+		probe.LastError = ""
+	} else {
+		probe.LastFailure = at
+		probe.LastError = probeErr.Error()
+		if probe.FailingSince.IsZero() {
+			probe.FailingSince = at
+		}
+	}
+	probes[contextName] = probe
+
+	data, err := json.MarshalIndent(probes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding probe history: %w", err)
+	}
+
+	if err := os.MkdirAll(GetStateDir(), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+	if err := os.WriteFile(probesPath(), data, 0o600); err != nil {
+		return fmt.Errorf("error writing probe history: %w", err)
+	}
+	return nil
+}
+
+// Unused returns the contexts that have not been switched to since cutoff,
+// mapped to the time they were last used (zero if never)
+//
+// Contexts that were never used are only reported when the history itself
+// started before cutoff, so a fresh install does not flag everything.
+func Unused(contextNames []string, events []Event, cutoff time.Time) map[string]time.Time {
+	unused := make(map[string]time.Time)
+	if len(events) == 0 {
+		return unused
+	}
+
+	historyStart := events[0].Time
+	for _, event := range events {
+		if event.Time.Before(historyStart) {
+			historyStart = event.Time
+		}
+	}
+
+	lastUsed := LastUsed(events)
+	for _, name := range contextNames {
+		last, used := lastUsed[name]
+		if used && last.Before(cutoff) {
+			unused[name] = last
+		} else if !used && historyStart.Before(cutoff) {
+			unused[name] = time.Time{}
+		}
+	}
+	return unused
+}
+
+// Unreachable returns the contexts whose cluster has been failing probes
+// since before cutoff, mapped to the time the failures started
+func Unreachable(contextNames []string, probes map[string]Probe, cutoff time.Time) map[string]time.Time {
+	unreachable := make(map[string]time.Time)
+	for _, name := range contextNames {
+		probe, exists := probes[name]
+		if !exists || probe.FailingSince.IsZero() {
+			continue
+		}
+		if probe.FailingSince.Before(cutoff) {
+			unreachable[name] = probe.FailingSince
+		}
+	}
+	return unreachable
+}
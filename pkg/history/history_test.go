@@ -0,0 +1,250 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// useTempStateDir points the state directory at a temporary directory
+func useTempStateDir(t *testing.T) func() {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "kontext-state-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	originalEnv := os.Getenv(StateDirEnv)
+	_ = os.Setenv(StateDirEnv, tmpDir)
+
+	return func() {
+		_ = os.Setenv(StateDirEnv, originalEnv)
+		_ = os.RemoveAll(tmpDir)
+	}
+}
+
+func TestRecordAndLoadSwitches(t *testing.T) {
+	defer useTempStateDir(t)()
+
+	events, err := LoadSwitches()
+	if err != nil {
+		t.Fatalf("LoadSwitches() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("LoadSwitches() on empty history = %d events, want 0", len(events))
+	}
+
+	first := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	if err := RecordSwitch(Event{Time: first, Context: "dev", Namespace: "default"}); err != nil {
+		t.Fatalf("RecordSwitch() error = %v", err)
+	}
+	if err := RecordSwitch(Event{Time: second, Context: "prod", PreviousContext: "dev"}); err != nil {
+		t.Fatalf("RecordSwitch() error = %v", err)
+	}
+
+	events, err = LoadSwitches()
+	if err != nil {
+		t.Fatalf("LoadSwitches() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("LoadSwitches() = %d events, want 2", len(events))
+	}
+	if events[1].Context != "prod" || events[1].PreviousContext != "dev" {
+		t.Errorf("LoadSwitches() second event = %+v", events[1])
+	}
+
+	lastUsed := LastUsed(events)
+	if !lastUsed["prod"].Equal(second) {
+		t.Errorf("LastUsed()[prod] = %v, want %v", lastUsed["prod"], second)
+	}
+}
+
+func TestRecordProbe(t *testing.T) {
+	defer useTempStateDir(t)()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []struct {
+		name             string
+		err              error
+		at               time.Time
+		wantFailingSince time.Time
+	}{
+		{"first failure starts the streak", errors.New("timeout"), start, start},
+		{"later failure keeps the streak", errors.New("timeout"), start.Add(24 * time.Hour), start},
+		{"success clears the streak", nil, start.Add(48 * time.Hour), time.Time{}},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if err := RecordProbe("ctx", step.err, step.at); err != nil {
+				t.Fatalf("RecordProbe() error = %v", err)
+			}
+			probes, err := LoadProbes()
+			if err != nil {
+				t.Fatalf("LoadProbes() error = %v", err)
+			}
+			if !probes["ctx"].FailingSince.Equal(step.wantFailingSince) {
+				t.Errorf("FailingSince = %v, want %v", probes["ctx"].FailingSince, step.wantFailingSince)
+			}
+		})
+	}
+}
+
+func TestUnused(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := now.AddDate(0, 0, -30)
+	events := []Event{
+		{Time: now.AddDate(0, 0, -90), Context: "old"},
+		{Time: now.AddDate(0, 0, -1), Context: "recent"},
+	}
+
+	unused := Unused([]string{"old", "recent", "never"}, events, cutoff)
+
+	if _, ok := unused["old"]; !ok {
+		t.Errorf("Unused() missing context old")
+	}
+	if _, ok := unused["recent"]; ok {
+		t.Errorf("Unused() reported recently used context")
+	}
+	if last, ok := unused["never"]; !ok || !last.IsZero() {
+		t.Errorf("Unused()[never] = %v, %v, want zero time", last, ok)
+	}
+
+	// A history younger than the cutoff never reports unused contexts
+	fresh := []Event{{Time: now.AddDate(0, 0, -1), Context: "recent"}}
+	if got := Unused([]string{"recent", "never"}, fresh, cutoff); len(got) != 0 {
+		t.Errorf("Unused() with fresh history = %v, want none", got)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	probes := map[string]Probe{
+		"down":    {FailingSince: now.AddDate(0, 0, -10)},
+		"flaky":   {FailingSince: now.AddDate(0, 0, -1)},
+		"healthy": {LastSuccess: now},
+	}
+
+	unreachable := Unreachable([]string{"down", "flaky", "healthy", "unknown"}, probes, now.AddDate(0, 0, -7))
+	if len(unreachable) != 1 {
+		t.Fatalf("Unreachable() = %v, want only down", unreachable)
+	}
+	if _, ok := unreachable["down"]; !ok {
+		t.Errorf("Unreachable() missing context down")
+	}
+}
+
+func TestElevation(t *testing.T) {
+	defer useTempStateDir(t)()
+
+	if elevation, err := LoadElevation(); err != nil || elevation != nil {
+		t.Fatalf("LoadElevation() without a file = %v, %v", elevation, err)
+	}
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := Elevation{
+		Context:           "prod",
+		Expires:           start.Add(30 * time.Minute),
+		FallbackContext:   "dev",
+		FallbackNamespace: "apps",
+	}
+	if err := SaveElevation(want); err != nil {
+		t.Fatalf("SaveElevation() error = %v", err)
+	}
+
+	elevation, err := LoadElevation()
+	if err != nil {
+		t.Fatalf("LoadElevation() error = %v", err)
+	}
+	if *elevation != want {
+		t.Errorf("LoadElevation() = %+v, want %+v", *elevation, want)
+	}
+
+	if elevation.Expired(start.Add(29*time.Minute)) || !elevation.Expired(start.Add(30*time.Minute)) {
+		t.Error("Expired() should turn true exactly at the expiry")
+	}
+	if got := elevation.Remaining(start.Add(20 * time.Minute)); got != 10*time.Minute {
+		t.Errorf("Remaining() = %v, want 10m", got)
+	}
+	if got := elevation.Remaining(start.Add(time.Hour)); got != 0 {
+		t.Errorf("Remaining() after expiry = %v, want 0", got)
+	}
+
+	if err := ClearElevation(); err != nil {
+		t.Fatalf("ClearElevation() error = %v", err)
+	}
+	if err := ClearElevation(); err != nil {
+		t.Errorf("ClearElevation() twice error = %v", err)
+	}
+	if elevation, _ := LoadElevation(); elevation != nil {
+		t.Errorf("LoadElevation() after ClearElevation() = %+v", elevation)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Time: start, Context: "dev", Namespace: "default"},
+		{Time: start.Add(time.Hour), Context: "prod", Namespace: "payments", PreviousContext: "dev", PreviousNamespace: "default"},
+		{Time: start.Add(90 * time.Minute), Context: "prod", Namespace: "orders", PreviousContext: "prod", PreviousNamespace: "payments"},
+		{Time: start.Add(2 * time.Hour), Context: "dev", Namespace: "default", PreviousContext: "prod", PreviousNamespace: "orders"},
+	}
+	now := start.Add(5 * time.Hour)
+
+	usage := Summarize(events, time.Time{}, now)
+	if len(usage) != 2 || usage[0].Name != "dev" || usage[1].Name != "prod" {
+		t.Fatalf("Summarize() = %+v, want dev then prod", usage)
+	}
+	dev, prod := usage[0], usage[1]
+	if dev.Switches != 2 || dev.TimeSpent != 4*time.Hour || !dev.LastUsed.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("dev usage = %+v, want 2 switches, 4h, last used at 11:00", dev.Usage)
+	}
+	if prod.Switches != 1 || prod.TimeSpent != time.Hour {
+		t.Errorf("prod usage = %+v, want 1 switch (namespace changes don't count) and 1h", prod.Usage)
+	}
+	if len(prod.Namespaces) != 2 || prod.Namespaces[0].TimeSpent != 30*time.Minute || prod.Namespaces[1].Switches != 1 {
+		t.Errorf("prod namespaces = %+v, want payments and orders with 30m each", prod.Namespaces)
+	}
+	if prod.Namespaces[0].Name != "orders" {
+		t.Errorf("prod namespaces with equal time should be sorted by name, got %s first", prod.Namespaces[0].Name)
+	}
+
+	// A window starting during the prod switch counts the rest of it, but not the switch
+	usage = Summarize(events, start.Add(75*time.Minute), now)
+	for _, u := range usage {
+		switch u.Name {
+		case "prod":
+			if u.Switches != 0 || u.TimeSpent != 45*time.Minute {
+				t.Errorf("prod usage since 10:15 = %+v, want 0 switches and 45m", u.Usage)
+			}
+		case "dev":
+			if u.Switches != 1 || u.TimeSpent != 3*time.Hour {
+				t.Errorf("dev usage since 10:15 = %+v, want 1 switch and 3h", u.Usage)
+			}
+		}
+	}
+}
+
+func TestRecordNamespace(t *testing.T) {
+	defer useTempStateDir(t)()
+
+	if ns, err := LastNamespace("prod"); err != nil || ns != "" {
+		t.Errorf("LastNamespace() without state = %q, %v, want empty", ns, err)
+	}
+
+	for _, change := range [][2]string{{"prod", "payments"}, {"dev", "sandbox"}, {"prod", "orders"}} {
+		if err := RecordNamespace(change[0], change[1]); err != nil {
+			t.Fatalf("RecordNamespace() error = %v", err)
+		}
+	}
+
+	if ns, err := LastNamespace("prod"); err != nil || ns != "orders" {
+		t.Errorf("LastNamespace(prod) = %q, %v, want orders", ns, err)
+	}
+	if ns, _ := LastNamespace("dev"); ns != "sandbox" {
+		t.Errorf("LastNamespace(dev) = %q, want sandbox", ns)
+	}
+}
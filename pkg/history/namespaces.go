@@ -0,0 +1,64 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// namespacesPath returns the path of the last used namespaces file
+func namespacesPath() string {
+	return filepath.Join(GetStateDir(), "namespaces.json")
+}
+
+// LoadNamespaces returns the namespace last chosen in each context
+func LoadNamespaces() (map[string]string, error) {
+	namespaces := make(map[string]string)
+
+	data, err := os.ReadFile(namespacesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return namespaces, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading last used namespaces: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &namespaces); err != nil {
+		return nil, fmt.Errorf("error parsing last used namespaces %s: %w", namespacesPath(), err)
+	}
+	return namespaces, nil
+}
+
+// LastNamespace returns the namespace last chosen in a context, or "" if none was
+func LastNamespace(contextName string) (string, error) {
+	namespaces, err := LoadNamespaces()
+	if err != nil {
+		return "", err
+	}
+	return namespaces[contextName], nil
+}
+
+// RecordNamespace remembers the namespace chosen in a context
+// It is kept independently of the kubeconfig, so it survives tools rewriting it.
+func RecordNamespace(contextName, namespace string) error {
+	namespaces, err := LoadNamespaces()
+	if err != nil {
+		return err
+	}
+	namespaces[contextName] = namespace
+
+	data, err := json.MarshalIndent(namespaces, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding last used namespaces: %w", err)
+	}
+
+	if err := os.MkdirAll(GetStateDir(), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+	if err := os.WriteFile(namespacesPath(), data, 0o600); err != nil {
+		return fmt.Errorf("error writing last used namespaces: %w", err)
+	}
+	return nil
+}
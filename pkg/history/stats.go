@@ -0,0 +1,105 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Usage summarizes how a context or namespace was used
+type Usage struct {
+	Name string
+	// Switches counts the switches to it
+	Switches int
+	// LastUsed is the time it was last switched to
+	LastUsed time.Time
+	// TimeSpent is the time until the next switch, summed over every switch to it
+	TimeSpent time.Duration
+}
+
+// ContextUsage is the usage of a context and of its namespaces
+type ContextUsage struct {
+	Usage
+	// Namespaces are sorted by time spent, longest first
+	Namespaces []Usage
+}
+
+// Summarize returns the usage of every context in the switch history, sorted
+// by time spent, longest first
+//
+// Each switch counts until the next one, and the last switch until now. Only
+// the time after since is counted, including the rest of a switch made before
+// it; a zero since counts everything. Events must be oldest first, as
+// LoadSwitches returns them. Namespace changes count as switches of the
+// namespace, not of the context.
+func Summarize(events []Event, since, now time.Time) []ContextUsage {
+	type namespaceKey struct{ context, namespace string }
+	contexts := make(map[string]*ContextUsage)
+	namespaces := make(map[namespaceKey]*Usage)
+
+	for i, event := range events {
+		end := now
+		if i+1 < len(events) {
+			end = events[i+1].Time
+		}
+		if !end.After(since) {
+			continue
+		}
+		start := event.Time
+		inWindow := !start.Before(since)
+		if !inWindow {
+			start = since
+		}
+
+		ctx, exists := contexts[event.Context]
+		if !exists {
+			ctx = &ContextUsage{Usage: Usage{Name: event.Context}}
+			contexts[event.Context] = ctx
+		}
+		record(&ctx.Usage, event, start, end, inWindow && event.Context != event.PreviousContext)
+
+		if event.Namespace == "" {
+			continue
+		}
+		key := namespaceKey{event.Context, event.Namespace}
+		ns, exists := namespaces[key]
+		if !exists {
+			ns = &Usage{Name: event.Namespace}
+			namespaces[key] = ns
+		}
+		record(ns, event, start, end, inWindow)
+	}
+
+	for key, ns := range namespaces {
+		ctx := contexts[key.context]
+		ctx.Namespaces = append(ctx.Namespaces, *ns)
+	}
+
+	usage := make([]ContextUsage, 0, len(contexts))
+	for _, ctx := range contexts {
+		sort.Slice(ctx.Namespaces, func(i, j int) bool { return busier(ctx.Namespaces[i], ctx.Namespaces[j]) })
+		usage = append(usage, *ctx)
+	}
+	sort.Slice(usage, func(i, j int) bool { return busier(usage[i].Usage, usage[j].Usage) })
+	return usage
+}
+
+// record adds the interval from start to end of an event to a usage
+func record(usage *Usage, event Event, start, end time.Time, counted bool) {
+	if counted {
+		usage.Switches++
+	}
+	if event.Time.After(usage.LastUsed) {
+		usage.LastUsed = event.Time
+	}
+	if end.After(start) {
+		usage.TimeSpent += end.Sub(start)
+	}
+}
+
+// busier orders usage by time spent, longest first, then by name
+func busier(a, b Usage) bool {
+	if a.TimeSpent != b.TimeSpent {
+		return a.TimeSpent > b.TimeSpent
+	}
+	return a.Name < b.Name
+}
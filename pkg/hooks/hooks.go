@@ -0,0 +1,96 @@
+// Package hooks runs the user's commands around context and namespace changes
+//
+// Hooks are shell commands run with sh -c. They receive the change in
+// environment variables, so a single script can handle every context.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Environment variables passed to hooks
+const (
+	EnvPhase        = "KONTEXT_HOOK"
+	EnvChange       = "KONTEXT_CHANGE"
+	EnvOldContext   = "KONTEXT_OLD_CONTEXT"
+	EnvOldNamespace = "KONTEXT_OLD_NAMESPACE"
+	EnvNewContext   = "KONTEXT_NEW_CONTEXT"
+	EnvNewNamespace = "KONTEXT_NEW_NAMESPACE"
+)
+
+var (
+	// ErrHookFailed is returned when a hook exits with a non-zero status
+	ErrHookFailed = errors.New("hook failed")
+	// ErrHookTimeout is returned when a hook runs longer than its timeout
+	ErrHookTimeout = errors.New("hook timed out")
+)
+
+// Change describes a context or namespace change
+type Change struct {
+	// Kind is "context" or "namespace"
+	Kind         string
+	OldContext   string
+	OldNamespace string
+	NewContext   string
+	NewNamespace string
+}
+
+// Env returns the environment variables describing the change to a hook of phase
+func (c Change) Env(phase string) []string {
+	return []string{
+		EnvPhase + "=" + phase,
+		EnvChange + "=" + c.Kind,
+		EnvOldContext + "=" + c.OldContext,
+		EnvOldNamespace + "=" + c.OldNamespace,
+		EnvNewContext + "=" + c.NewContext,
+		EnvNewNamespace + "=" + c.NewNamespace,
+	}
+}
+
+// Runner runs hooks
+// Hooks inherit kontext's environment plus the change variables. Their
+// output goes to Output, which should not be kontext's stdout so prompt
+// segments and structured output stay clean.
+type Runner struct {
+	Stdin  io.Reader
+	Output io.Writer
+}
+
+// NewRunner creates a Runner writing hook output to stderr
+// stdin is only connected when interactive, so hooks such as `aws sso login` can prompt.
+func NewRunner(interactive bool) *Runner {
+	r := &Runner{Output: os.Stderr}
+	if interactive {
+		r.Stdin = os.Stdin
+	}
+	return r
+}
+
+// Run runs a hook command for a change, killing it after timeout
+func (r *Runner) Run(command, phase string, change Change, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), change.Env(phase)...)
+	cmd.Stdin = r.Stdin
+	cmd.Stdout = r.Output
+	cmd.Stderr = r.Output
+	// Don't wait forever for children that keep the output open
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %s", ErrHookTimeout, timeout, command)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrHookFailed, command, err)
+	}
+	return nil
+}
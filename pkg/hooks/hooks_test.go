@@ -0,0 +1,45 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	r := &Runner{Output: &out}
+	change := Change{Kind: "context", OldContext: "dev", OldNamespace: "apps", NewContext: "prod", NewNamespace: "default"}
+
+	err := r.Run(`echo "$KONTEXT_HOOK $KONTEXT_CHANGE $KONTEXT_OLD_CONTEXT/$KONTEXT_OLD_NAMESPACE $KONTEXT_NEW_CONTEXT/$KONTEXT_NEW_NAMESPACE"`, "pre", change, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "pre context dev/apps prod/default" {
+		t.Errorf("Run() output = %q", got)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	r := &Runner{Output: &bytes.Buffer{}}
+
+	err := r.Run("exit 3", "pre", Change{}, 5*time.Second)
+	if !errors.Is(err, ErrHookFailed) || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Run() error = %v, want %v with the exit status", err, ErrHookFailed)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	r := &Runner{Output: &bytes.Buffer{}}
+
+	start := time.Now()
+	err := r.Run("sleep 10", "post", Change{}, 100*time.Millisecond)
+	if !errors.Is(err, ErrHookTimeout) {
+		t.Errorf("Run() error = %v, want %v", err, ErrHookTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %v, the hook wasn't stopped", elapsed)
+	}
+}
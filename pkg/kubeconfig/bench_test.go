@@ -0,0 +1,116 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// writeLargeKubeConfig writes a kubeconfig similar to a big EKS/GKE one:
+// many contexts whose clusters and users embed certificate data (about 3MB)
+func writeLargeKubeConfig(b *testing.B, contexts int) string {
+	b.Helper()
+
+	certificate := bytes.Repeat([]byte("0123456789abcdef"), 64)
+
+	config := api.NewConfig()
+	for i := 0; i < contexts; i++ {
+		name := fmt.Sprintf("arn:aws:eks:eu-west-1:123456789012:cluster/cluster-%04d", i)
+		config.Clusters[name] = &api.Cluster{
+			Server:                   fmt.Sprintf("https://%04d.gr7.eu-west-1.eks.amazonaws.com", i),
+			CertificateAuthorityData: certificate,
+		}
+		config.AuthInfos[name] = &api.AuthInfo{
+			ClientCertificateData: certificate,
+			Exec: &api.ExecConfig{
+				APIVersion: "client.authentication.k8s.io/v1beta1",
+				Command:    "aws",
+				Args:       []string{"eks", "get-token", "--cluster-name", fmt.Sprintf("cluster-%04d", i)},
+			},
+		}
+		config.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name, Namespace: "default"}
+	}
+	config.CurrentContext = "arn:aws:eks:eu-west-1:123456789012:cluster/cluster-0000"
+
+	tmpDir, err := os.MkdirTemp("", "kontext-bench-*")
+	if err != nil {
+		b.Fatalf("Failed to create temp dir: %v", err)
+	}
+	b.Cleanup(func() { _ = os.RemoveAll(tmpDir) })
+
+	configPath := filepath.Join(tmpDir, "config")
+	if err := clientcmd.WriteToFile(*config, configPath); err != nil {
+		b.Fatalf("Failed to write test config: %v", err)
+	}
+	return configPath
+}
+
+// benchmarkSwitchTargets alternates between two contexts so every switch writes
+var benchmarkSwitchTargets = []string{
+	"arn:aws:eks:eu-west-1:123456789012:cluster/cluster-0001",
+	"arn:aws:eks:eu-west-1:123456789012:cluster/cluster-0002",
+}
+
+// BenchmarkSwitchPerCallLoad performs a switch the way kontext used to: every
+// helper call loads and parses the kubeconfig again
+func BenchmarkSwitchPerCallLoad(b *testing.B) {
+	configPath := writeLargeKubeConfig(b, 1500)
+
+	originalEnv := os.Getenv("KUBECONFIG")
+	defer func() {
+		_ = os.Setenv("KUBECONFIG", originalEnv)
+	}()
+	_ = os.Setenv("KUBECONFIG", configPath)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		target := benchmarkSwitchTargets[i%2]
+		if _, err := GetContexts(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := GetCurrentContext(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := GetCurrentNamespace(); err != nil {
+			b.Fatal(err)
+		}
+		if err := SwitchContext(target); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := GetNamespaceForContext(target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSwitchSnapshot performs the same switch against a single Manager
+// snapshot: one parse and one write
+func BenchmarkSwitchSnapshot(b *testing.B) {
+	configPath := writeLargeKubeConfig(b, 1500)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		target := benchmarkSwitchTargets[i%2]
+		m := NewManager(WithPath(configPath))
+		if _, err := m.GetContexts(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := m.GetCurrentContext(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := m.GetCurrentNamespace(); err != nil {
+			b.Fatal(err)
+		}
+		if err := m.SwitchContext(target); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := m.GetNamespaceForContext(target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
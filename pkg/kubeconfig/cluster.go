@@ -0,0 +1,150 @@
+package kubeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterClient talks to the cluster of a context
+//
+// The default implementation uses client-go. Tests and embedding tools can
+// provide their own with WithClusterClient.
+type ClusterClient interface {
+	// ListNamespaces returns the namespaces of the context's cluster
+	// Connection failures must be wrapped in ErrClusterUnreachable.
+	ListNamespaces(config *api.Config, contextName string) ([]string, error)
+
+	// Probe checks whether the context's cluster answers within the timeout
+	// It returns nil if the API server responded (even with an authorization
+	// error) and an error wrapping ErrClusterUnreachable otherwise.
+	Probe(config *api.Config, contextName string, timeout time.Duration) error
+}
+
+// NamespaceCreator is implemented by cluster clients that can create namespaces
+//
+// It is separate from ClusterClient so existing implementations keep working;
+// the Manager uses it when its ClusterClient implements it.
+type NamespaceCreator interface {
+	// CreateNamespace creates a namespace with labels in the context's cluster
+	// Creating a namespace that already exists is not an error. Denials must
+	// be wrapped in ErrForbidden and connection failures in ErrClusterUnreachable.
+	CreateNamespace(config *api.Config, contextName, namespace string, labels map[string]string) error
+}
+
+// kubeClusterClient is the client-go based ClusterClient
+// A zero timeout keeps the client-go default for ListNamespaces.
+type kubeClusterClient struct {
+	timeout time.Duration
+}
+
+// ListNamespaces implements ClusterClient
+func (c kubeClusterClient) ListNamespaces(config *api.Config, contextName string) ([]string, error) {
+	clientset, err := clientsetForContext(config, contextName, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to list namespaces from the cluster
+	namespaceList, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, classifyClusterError(err)
+	}
+
+	// Extract namespace names from the response
+	namespaces := make([]string, 0, len(namespaceList.Items))
+	for _, ns := range namespaceList.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+
+	return namespaces, nil
+}
+
+// CreateNamespace implements NamespaceCreator
+func (c kubeClusterClient) CreateNamespace(config *api.Config, contextName, namespace string, labels map[string]string) error {
+	clientset, err := clientsetForContext(config, contextName, c.timeout)
+	if err != nil {
+		return err
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: labels}}
+	_, err = clientset.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{})
+	switch {
+	case err == nil, apierrors.IsAlreadyExists(err):
+		return nil
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	default:
+		return classifyClusterError(err)
+	}
+}
+
+// Probe implements ClusterClient
+func (kubeClusterClient) Probe(config *api.Config, contextName string, timeout time.Duration) error {
+	clientset, err := clientsetForContext(config, contextName, timeout)
+	if err != nil {
+		return err
+	}
+
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		if err := classifyClusterError(err); errors.Is(err, ErrClusterUnreachable) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProbeContext checks whether the cluster of a context answers within the timeout
+//
+// It returns nil if the API server responded (even with an authorization
+// error) and an error wrapping ErrClusterUnreachable otherwise.
+func ProbeContext(contextName string, timeout time.Duration) error {
+	return NewManager().ProbeContext(contextName, timeout)
+}
+
+// clientsetForContext creates a Kubernetes clientset for a context of the config
+// A zero timeout keeps the client-go default.
+func clientsetForContext(config *api.Config, contextName string, timeout time.Duration) (*kubernetes.Clientset, error) {
+	// Create client configuration for the specified context
+	clientConfig := clientcmd.NewNonInteractiveClientConfig(
+		*config,
+		contextName,
+		&clientcmd.ConfigOverrides{},
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+	)
+
+	// Get REST config for the context
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnreachable, err)
+	}
+	if timeout > 0 {
+		restConfig.Timeout = timeout
+	}
+
+	// Create the clientset
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClusterUnreachable, err)
+	}
+
+	return clientset, nil
+}
+
+// classifyClusterError wraps connection failures in ErrClusterUnreachable
+// API status errors are returned unchanged since the cluster did respond.
+func classifyClusterError(err error) error {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrClusterUnreachable, err)
+}
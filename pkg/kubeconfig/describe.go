@@ -0,0 +1,193 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Redacted replaces secret values in context details unless secrets are requested
+const Redacted = "REDACTED"
+
+// Authentication methods reported in UserDetails.AuthMethod
+const (
+	AuthMethodToken             = "token"
+	AuthMethodTokenFile         = "token-file"
+	AuthMethodClientCertificate = "client-certificate"
+	AuthMethodExec              = "exec"
+	AuthMethodAuthProvider      = "auth-provider"
+	AuthMethodBasic             = "basic"
+	AuthMethodNone              = "none"
+)
+
+// ContextDetails describes everything known about a context
+//
+// The kontext metadata fields (such as LastUsed) are not part of the
+// kubeconfig and are filled in by the caller.
+type ContextDetails struct {
+	Name      string         `json:"name"`
+	Current   bool           `json:"current"`
+	Namespace string         `json:"namespace"`
+	Source    string         `json:"source,omitempty"`
+	Cluster   ClusterDetails `json:"cluster"`
+	User      UserDetails    `json:"user"`
+	LastUsed  *time.Time     `json:"lastUsed,omitempty"`
+}
+
+// ClusterDetails describes the cluster referenced by a context
+type ClusterDetails struct {
+	Name                  string `json:"name"`
+	Server                string `json:"server,omitempty"`
+	CertificateAuthority  string `json:"certificateAuthority"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify"`
+	TLSServerName         string `json:"tlsServerName,omitempty"`
+	ProxyURL              string `json:"proxyURL,omitempty"`
+	Source                string `json:"source,omitempty"`
+	Missing               bool   `json:"missing,omitempty"`
+}
+
+// UserDetails describes the user (authInfo) referenced by a context
+type UserDetails struct {
+	Name              string               `json:"name"`
+	AuthMethod        string               `json:"authMethod"`
+	Token             string               `json:"token,omitempty"`
+	TokenFile         string               `json:"tokenFile,omitempty"`
+	ClientCertificate string               `json:"clientCertificate,omitempty"`
+	ClientKey         string               `json:"clientKey,omitempty"`
+	Username          string               `json:"username,omitempty"`
+	Password          string               `json:"password,omitempty"`
+	Impersonate       string               `json:"impersonate,omitempty"`
+	Exec              *ExecDetails         `json:"exec,omitempty"`
+	AuthProvider      *AuthProviderDetails `json:"authProvider,omitempty"`
+	Source            string               `json:"source,omitempty"`
+	Missing           bool                 `json:"missing,omitempty"`
+}
+
+// ExecDetails describes an exec credential plugin
+type ExecDetails struct {
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	APIVersion string            `json:"apiVersion,omitempty"`
+}
+
+// AuthProviderDetails describes a legacy auth-provider plugin
+type AuthProviderDetails struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// DescribeContext returns the details of a context
+//
+// Tokens, passwords, exec plugin environment values and auth-provider
+// configuration are replaced with Redacted unless showSecrets is true.
+// Embedded certificate and key data is never included, only its size.
+func DescribeContext(config *api.Config, contextName string, showSecrets bool) (*ContextDetails, error) {
+	ctx, exists := config.Contexts[contextName]
+	if !exists || ctx == nil {
+		return nil, notFound(ErrContextNotFound, contextName)
+	}
+
+	redact := func(value string) string {
+		if value == "" || showSecrets {
+			return value
+		}
+		return Redacted
+	}
+
+	details := &ContextDetails{
+		Name:      contextName,
+		Current:   config.CurrentContext == contextName,
+		Namespace: ctx.Namespace,
+		Source:    ctx.LocationOfOrigin,
+		Cluster:   ClusterDetails{Name: ctx.Cluster},
+		User:      UserDetails{Name: ctx.AuthInfo, AuthMethod: AuthMethodNone},
+	}
+	if details.Namespace == "" {
+		details.Namespace = "default"
+	}
+
+	if cluster, exists := config.Clusters[ctx.Cluster]; exists && cluster != nil {
+		details.Cluster.Server = cluster.Server
+		details.Cluster.CertificateAuthority = dataSource(cluster.CertificateAuthority, cluster.CertificateAuthorityData, "system trust store")
+		details.Cluster.InsecureSkipTLSVerify = cluster.InsecureSkipTLSVerify
+		details.Cluster.TLSServerName = cluster.TLSServerName
+		details.Cluster.ProxyURL = cluster.ProxyURL
+		details.Cluster.Source = cluster.LocationOfOrigin
+	} else {
+		details.Cluster.Missing = true
+	}
+
+	authInfo, exists := config.AuthInfos[ctx.AuthInfo]
+	if !exists || authInfo == nil {
+		details.User.Missing = ctx.AuthInfo != ""
+		return details, nil
+	}
+
+	user := &details.User
+	user.Source = authInfo.LocationOfOrigin
+	user.Token = redact(authInfo.Token)
+	user.TokenFile = authInfo.TokenFile
+	user.Username = authInfo.Username
+	user.Password = redact(authInfo.Password)
+	user.Impersonate = authInfo.Impersonate
+	if authInfo.ClientCertificate != "" || len(authInfo.ClientCertificateData) > 0 {
+		user.ClientCertificate = dataSource(authInfo.ClientCertificate, authInfo.ClientCertificateData, "")
+		user.ClientKey = dataSource(authInfo.ClientKey, authInfo.ClientKeyData, "")
+	}
+
+	if authInfo.Exec != nil {
+		user.Exec = &ExecDetails{
+			Command:    authInfo.Exec.Command,
+			Args:       authInfo.Exec.Args,
+			APIVersion: authInfo.Exec.APIVersion,
+		}
+		if len(authInfo.Exec.Env) > 0 {
+			user.Exec.Env = make(map[string]string, len(authInfo.Exec.Env))
+			for _, env := range authInfo.Exec.Env {
+				user.Exec.Env[env.Name] = redact(env.Value)
+			}
+		}
+	}
+
+	if authInfo.AuthProvider != nil {
+		user.AuthProvider = &AuthProviderDetails{Name: authInfo.AuthProvider.Name}
+		if len(authInfo.AuthProvider.Config) > 0 {
+			user.AuthProvider.Config = make(map[string]string, len(authInfo.AuthProvider.Config))
+			for key, value := range authInfo.AuthProvider.Config {
+				user.AuthProvider.Config[key] = redact(value)
+			}
+		}
+	}
+
+	// Report the primary authentication method of the user
+	switch {
+	case user.ClientCertificate != "":
+		user.AuthMethod = AuthMethodClientCertificate
+	case authInfo.Token != "":
+		user.AuthMethod = AuthMethodToken
+	case authInfo.TokenFile != "":
+		user.AuthMethod = AuthMethodTokenFile
+	case authInfo.Exec != nil:
+		user.AuthMethod = AuthMethodExec
+	case authInfo.AuthProvider != nil:
+		user.AuthMethod = AuthMethodAuthProvider
+	case authInfo.Username != "":
+		user.AuthMethod = AuthMethodBasic
+	}
+
+	return details, nil
+}
+
+// dataSource describes where certificate or key material comes from
+func dataSource(path string, data []byte, fallback string) string {
+	switch {
+	case len(data) > 0:
+		return fmt.Sprintf("embedded (%d bytes)", len(data))
+	case path != "":
+		return "file " + path
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,93 @@
+package kubeconfig
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestDescribeContext(t *testing.T) {
+	config := api.NewConfig()
+	config.Clusters["cluster1"] = &api.Cluster{
+		Server:                   "https://cluster1.example.com",
+		CertificateAuthorityData: []byte("ca-data"),
+		ProxyURL:                 "http://proxy.example.com:3128",
+	}
+	config.AuthInfos["token-user"] = &api.AuthInfo{Token: "secret-token"}
+	config.AuthInfos["exec-user"] = &api.AuthInfo{Exec: &api.ExecConfig{
+		Command: "aws",
+		Args:    []string{"eks", "get-token"},
+		Env:     []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: "prod"}},
+	}}
+	config.Contexts["token"] = &api.Context{Cluster: "cluster1", AuthInfo: "token-user", Namespace: "apps"}
+	config.Contexts["exec"] = &api.Context{Cluster: "cluster1", AuthInfo: "exec-user"}
+	config.Contexts["broken"] = &api.Context{Cluster: "missing", AuthInfo: "missing-user"}
+	config.CurrentContext = "token"
+
+	t.Run("Redacts secrets by default", func(t *testing.T) {
+		details, err := DescribeContext(config, "token", false)
+		if err != nil {
+			t.Fatalf("DescribeContext() error = %v", err)
+		}
+		if !details.Current || details.Namespace != "apps" {
+			t.Errorf("DescribeContext() current = %v, namespace = %v", details.Current, details.Namespace)
+		}
+		if details.User.AuthMethod != AuthMethodToken {
+			t.Errorf("AuthMethod = %v, want %v", details.User.AuthMethod, AuthMethodToken)
+		}
+		if details.User.Token != Redacted {
+			t.Errorf("Token = %v, want %v", details.User.Token, Redacted)
+		}
+		if details.Cluster.CertificateAuthority != "embedded (7 bytes)" {
+			t.Errorf("CertificateAuthority = %v", details.Cluster.CertificateAuthority)
+		}
+		if details.Cluster.ProxyURL != "http://proxy.example.com:3128" {
+			t.Errorf("ProxyURL = %v", details.Cluster.ProxyURL)
+		}
+	})
+
+	t.Run("Shows secrets on request", func(t *testing.T) {
+		details, err := DescribeContext(config, "token", true)
+		if err != nil {
+			t.Fatalf("DescribeContext() error = %v", err)
+		}
+		if details.User.Token != "secret-token" {
+			t.Errorf("Token = %v, want secret-token", details.User.Token)
+		}
+	})
+
+	t.Run("Describes exec plugins", func(t *testing.T) {
+		details, err := DescribeContext(config, "exec", false)
+		if err != nil {
+			t.Fatalf("DescribeContext() error = %v", err)
+		}
+		if details.User.AuthMethod != AuthMethodExec || details.User.Exec == nil {
+			t.Fatalf("AuthMethod = %v, Exec = %v", details.User.AuthMethod, details.User.Exec)
+		}
+		if details.User.Exec.Command != "aws" || len(details.User.Exec.Args) != 2 {
+			t.Errorf("Exec = %+v", details.User.Exec)
+		}
+		if details.User.Exec.Env["AWS_PROFILE"] != Redacted {
+			t.Errorf("Exec env AWS_PROFILE = %v, want %v", details.User.Exec.Env["AWS_PROFILE"], Redacted)
+		}
+		if details.Namespace != "default" {
+			t.Errorf("Namespace = %v, want default", details.Namespace)
+		}
+	})
+
+	t.Run("Reports missing cluster and user", func(t *testing.T) {
+		details, err := DescribeContext(config, "broken", false)
+		if err != nil {
+			t.Fatalf("DescribeContext() error = %v", err)
+		}
+		if !details.Cluster.Missing || !details.User.Missing {
+			t.Errorf("Missing cluster = %v, user = %v, want both true", details.Cluster.Missing, details.User.Missing)
+		}
+	})
+
+	t.Run("Unknown context", func(t *testing.T) {
+		if _, err := DescribeContext(config, "nonexistent", false); err == nil {
+			t.Error("DescribeContext() with unknown context should fail")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by this package
+//
+// Errors are wrapped with details such as the entry name or the underlying
+// cause, so callers should compare them with errors.Is.
+var (
+	// ErrKubeConfigLoad is returned when the kubeconfig (or one of its fragments) can't be read or parsed
+	ErrKubeConfigLoad = errors.New("cannot load kubeconfig")
+
+	// ErrKubeConfigSave is returned when the kubeconfig (or one of its fragments) can't be written
+	ErrKubeConfigSave = errors.New("cannot save kubeconfig")
+
+	// ErrNoCurrentContext is returned when an operation needs the current context but none is set
+	ErrNoCurrentContext = errors.New("no current context set")
+
+	// ErrContextNotFound is returned when a named context does not exist
+	ErrContextNotFound = errors.New("context not found")
+
+	// ErrClusterNotFound is returned when a named cluster does not exist
+	ErrClusterNotFound = errors.New("cluster not found")
+
+	// ErrUserNotFound is returned when a named user (authInfo) does not exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrNoMatchingContexts is returned when a pattern matches no context
+	ErrNoMatchingContexts = errors.New("no matching contexts")
+
+	// ErrClusterUnreachable is returned when a context's cluster cannot be contacted
+	//
+	// API errors such as RBAC denials are not wrapped in it: they prove that the
+	// cluster answered.
+	ErrClusterUnreachable = errors.New("cluster unreachable")
+
+	// ErrForbidden is returned when the cluster denies a change, usually because of RBAC
+	// The API error is wrapped as well.
+	ErrForbidden = errors.New("forbidden by the cluster")
+
+	// ErrCreateNotSupported is returned when the cluster client can't create namespaces
+	ErrCreateNotSupported = errors.New("cluster client can't create namespaces")
+)
+
+// notFound wraps a not-found sentinel error with the name of the missing entry
+func notFound(sentinel error, name string) error {
+	return fmt.Errorf("%w: '%s'", sentinel, name)
+}
@@ -0,0 +1,316 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// FragmentGlobEnv is the environment variable used to enable fragment mode
+//
+// When set to a glob such as ~/.kube/configs.d/*.yaml, every matching file is
+// loaded and merged instead of the single file from GetKubeConfigPath.
+const FragmentGlobEnv = "KONTEXT_KUBECONFIG_GLOB"
+
+// OverlayPathEnv overrides the location of the overlay file used in fragment mode
+const OverlayPathEnv = "KONTEXT_OVERLAY"
+
+// fragmentGlob and overlayPath are set with SetFragments
+var fragmentGlob, overlayPath string
+
+// SetFragments configures fragment mode, for example from kontext's configuration
+// An empty glob or overlay falls back to FragmentGlobEnv and OverlayPathEnv.
+func SetFragments(glob, overlay string) {
+	fragmentGlob, overlayPath = glob, overlay
+}
+
+// GetFragmentGlob returns the configured fragment glob, or an empty string
+// when fragment mode is disabled. A leading ~ is expanded to $HOME.
+func GetFragmentGlob() string {
+	if fragmentGlob != "" {
+		return expandHome(fragmentGlob)
+	}
+	return expandHome(os.Getenv(FragmentGlobEnv))
+}
+
+// IsFragmentMode reports whether kubeconfig fragments are in use
+func IsFragmentMode() bool {
+	return GetFragmentGlob() != ""
+}
+
+// GetOverlayPath returns the path of the overlay file used in fragment mode
+//
+// The overlay stores the current context and any entries that do not belong
+// to a fragment, so that switching contexts never rewrites the fragments.
+func GetOverlayPath() string {
+	if overlayPath != "" {
+		return expandHome(overlayPath)
+	}
+	if path := os.Getenv(OverlayPathEnv); path != "" {
+		return expandHome(path)
+	}
+	return filepath.Join(os.Getenv("HOME"), ".kube", "kontext-overlay.yaml")
+}
+
+// GetFragmentPaths returns the sorted list of files matching the fragment glob
+// The overlay file is never treated as a fragment, even if the glob matches it.
+func GetFragmentPaths() ([]string, error) {
+	pattern := GetFragmentGlob()
+	if pattern == "" {
+		return nil, fmt.Errorf("fragment mode is not enabled (kubeconfig.glob and %s are not set)", FragmentGlobEnv)
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid glob '%s': %w", ErrKubeConfigLoad, pattern, err)
+	}
+
+	overlay := GetOverlayPath()
+	paths := make([]string, 0, len(matches))
+	for _, path := range matches {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if samePath(path, overlay) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// loadFragments loads every fragment and the overlay into a single merged config
+//
+// Entries are merged the same way kubectl merges KUBECONFIG lists: the first
+// file defining a name wins. Each entry keeps its LocationOfOrigin so that
+// writeFragments can route changes back to the file it came from.
+func loadFragments() (*api.Config, error) {
+	paths, err := GetFragmentPaths()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no fragments match '%s'", ErrKubeConfigLoad, GetFragmentGlob())
+	}
+
+	return loadFragmentFiles(paths, GetOverlayPath())
+}
+
+// loadFragmentFiles loads the given fragments and overlay into a single merged config
+func loadFragmentFiles(paths []string, overlayPath string) (*api.Config, error) {
+	merged := api.NewConfig()
+	for _, path := range paths {
+		fragment, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: fragment %s: %w", ErrKubeConfigLoad, path, err)
+		}
+		mergeInto(merged, fragment)
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = fragment.CurrentContext
+		}
+	}
+
+	overlay, err := loadOverlay(overlayPath)
+	if err != nil {
+		return nil, err
+	}
+	mergeInto(merged, overlay)
+	if overlay.CurrentContext != "" {
+		merged.CurrentContext = overlay.CurrentContext
+	}
+
+	return merged, nil
+}
+
+// loadOverlay loads the overlay file, returning an empty config if it does not exist yet
+func loadOverlay(path string) (*api.Config, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return api.NewConfig(), nil
+	}
+
+	overlay, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: overlay %s: %w", ErrKubeConfigLoad, path, err)
+	}
+	return overlay, nil
+}
+
+// mergeInto copies entries from src into dst without overwriting existing names
+func mergeInto(dst, src *api.Config) {
+	for name, cluster := range src.Clusters {
+		if _, exists := dst.Clusters[name]; !exists {
+			dst.Clusters[name] = cluster
+		}
+	}
+	for name, authInfo := range src.AuthInfos {
+		if _, exists := dst.AuthInfos[name]; !exists {
+			dst.AuthInfos[name] = authInfo
+		}
+	}
+	for name, ctx := range src.Contexts {
+		if _, exists := dst.Contexts[name]; !exists {
+			dst.Contexts[name] = ctx
+		}
+	}
+}
+
+// writeFragments writes a merged config back to the fragments it was loaded from
+//
+// Entries are written to the file named by their LocationOfOrigin and entries
+// that were removed from the merged config are removed from their fragment.
+// The current context and entries without a fragment go to the overlay file.
+// Fragments are only rewritten when their content actually changed.
+func writeFragments(config *api.Config) error {
+	paths, err := GetFragmentPaths()
+	if err != nil {
+		return err
+	}
+
+	return writeFragmentFiles(config, paths, GetOverlayPath())
+}
+
+// writeFragmentFiles writes a merged config back to the given fragments and overlay
+func writeFragmentFiles(config *api.Config, paths []string, overlayPath string) error {
+	for _, path := range paths {
+		fragment, err := clientcmd.LoadFromFile(path)
+		if err != nil {
+			return fmt.Errorf("%w: fragment %s: %w", ErrKubeConfigLoad, path, err)
+		}
+
+		changed := syncEntries(fragment.Contexts, config.Contexts, path, func(c *api.Context) string { return c.LocationOfOrigin }, nil)
+
+		// Clusters and users still referenced by a context in this fragment
+		// (for example a shadowed duplicate) are kept even if unused in the merged view
+		referenced := func(field func(*api.Context) string) func(string) bool {
+			return func(name string) bool {
+				for _, ctx := range fragment.Contexts {
+					if ctx != nil && field(ctx) == name {
+						return true
+					}
+				}
+				return false
+			}
+		}
+		changed = syncEntries(fragment.Clusters, config.Clusters, path, func(c *api.Cluster) string { return c.LocationOfOrigin },
+			referenced(func(c *api.Context) string { return c.Cluster })) || changed
+		changed = syncEntries(fragment.AuthInfos, config.AuthInfos, path, func(a *api.AuthInfo) string { return a.LocationOfOrigin },
+			referenced(func(c *api.Context) string { return c.AuthInfo })) || changed
+
+		// A fragment's own current-context is superseded by the overlay, but
+		// it must not keep pointing at a context that no longer exists.
+		if fragment.CurrentContext != "" {
+			if _, exists := config.Contexts[fragment.CurrentContext]; !exists {
+				fragment.CurrentContext = ""
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+		if err := clientcmd.WriteToFile(*fragment, path); err != nil {
+			return fmt.Errorf("%w: fragment %s: %w", ErrKubeConfigSave, path, err)
+		}
+	}
+
+	return writeOverlay(config, paths, overlayPath)
+}
+
+// syncEntries updates the entries of a single fragment from the merged config
+// and reports whether anything changed. Entries shadowed by an earlier
+// fragment are left untouched, and entries missing from the merged config are
+// removed unless keep reports that they are still needed.
+func syncEntries[T any](fragment, merged map[string]*T, path string, origin func(*T) string, keep func(string) bool) bool {
+	changed := false
+	for name, entry := range fragment {
+		current, exists := merged[name]
+		if !exists {
+			if keep != nil && keep(name) {
+				continue
+			}
+			delete(fragment, name)
+			changed = true
+			continue
+		}
+		if !samePath(origin(current), path) {
+			continue
+		}
+		if !reflect.DeepEqual(entry, current) {
+			fragment[name] = current
+			changed = true
+		}
+	}
+	return changed
+}
+
+// writeOverlay writes the current context and any entries that do not
+// originate from a fragment to the overlay file
+func writeOverlay(config *api.Config, fragmentPaths []string, overlayPath string) error {
+	overlay := api.NewConfig()
+	overlay.CurrentContext = config.CurrentContext
+
+	fromFragment := func(location string) bool {
+		for _, path := range fragmentPaths {
+			if samePath(location, path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for name, cluster := range config.Clusters {
+		if !fromFragment(cluster.LocationOfOrigin) {
+			overlay.Clusters[name] = cluster
+		}
+	}
+	for name, authInfo := range config.AuthInfos {
+		if !fromFragment(authInfo.LocationOfOrigin) {
+			overlay.AuthInfos[name] = authInfo
+		}
+	}
+	for name, ctx := range config.Contexts {
+		if !fromFragment(ctx.LocationOfOrigin) {
+			overlay.Contexts[name] = ctx
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(overlayPath), 0o755); err != nil {
+		return fmt.Errorf("%w: creating overlay directory: %w", ErrKubeConfigSave, err)
+	}
+	if err := clientcmd.WriteToFile(*overlay, overlayPath); err != nil {
+		return fmt.Errorf("%w: overlay %s: %w", ErrKubeConfigSave, overlayPath, err)
+	}
+	return nil
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path == "~" {
+		return os.Getenv("HOME")
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[2:])
+	}
+	return path
+}
+
+// samePath reports whether two paths refer to the same file location
+func samePath(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return absA == absB
+}
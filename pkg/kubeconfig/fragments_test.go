@@ -0,0 +1,194 @@
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// createTestFragments creates a fragments directory with two kubeconfig files
+// and points kontext at it. The returned function restores the environment.
+func createTestFragments(t *testing.T) (string, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "kontext-fragments-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+
+	fragmentsDir := filepath.Join(tmpDir, "configs.d")
+	if err := os.MkdirAll(fragmentsDir, 0o755); err != nil {
+		t.Fatalf("Failed to create fragments dir: %v", err)
+	}
+
+	dev := api.NewConfig()
+	dev.Clusters["dev-cluster"] = &api.Cluster{Server: "https://dev.example.com"}
+	dev.AuthInfos["dev-user"] = &api.AuthInfo{Token: "dev-token"}
+	dev.Contexts["dev"] = &api.Context{Cluster: "dev-cluster", AuthInfo: "dev-user", Namespace: "dev-ns"}
+	dev.CurrentContext = "dev"
+
+	prod := api.NewConfig()
+	prod.Clusters["prod-cluster"] = &api.Cluster{Server: "https://prod.example.com"}
+	prod.AuthInfos["prod-user"] = &api.AuthInfo{Token: "prod-token"}
+	prod.Contexts["prod"] = &api.Context{Cluster: "prod-cluster", AuthInfo: "prod-user"}
+	// Shadowed by the dev fragment, which sorts first
+	prod.Contexts["dev"] = &api.Context{Cluster: "prod-cluster", AuthInfo: "prod-user", Namespace: "shadowed"}
+
+	if err := clientcmd.WriteToFile(*dev, filepath.Join(fragmentsDir, "a-dev.yaml")); err != nil {
+		t.Fatalf("Failed to write dev fragment: %v", err)
+	}
+	if err := clientcmd.WriteToFile(*prod, filepath.Join(fragmentsDir, "b-prod.yaml")); err != nil {
+		t.Fatalf("Failed to write prod fragment: %v", err)
+	}
+
+	originalGlob := os.Getenv(FragmentGlobEnv)
+	originalOverlay := os.Getenv(OverlayPathEnv)
+	_ = os.Setenv(FragmentGlobEnv, filepath.Join(fragmentsDir, "*.yaml"))
+	_ = os.Setenv(OverlayPathEnv, filepath.Join(tmpDir, "overlay.yaml"))
+
+	return fragmentsDir, func() {
+		_ = os.Setenv(FragmentGlobEnv, originalGlob)
+		_ = os.Setenv(OverlayPathEnv, originalOverlay)
+		_ = os.RemoveAll(tmpDir)
+	}
+}
+
+func TestFragmentModeMergesContexts(t *testing.T) {
+	_, cleanup := createTestFragments(t)
+	defer cleanup()
+
+	contexts, err := GetContexts()
+	if err != nil {
+		t.Fatalf("GetContexts() error = %v", err)
+	}
+
+	if len(contexts) != 2 {
+		t.Errorf("GetContexts() count = %d, want 2", len(contexts))
+	}
+
+	// The first fragment defining a name wins
+	if contexts["dev"].Namespace != "dev-ns" {
+		t.Errorf("context dev namespace = %v, want dev-ns", contexts["dev"].Namespace)
+	}
+
+	current, err := GetCurrentContext()
+	if err != nil {
+		t.Fatalf("GetCurrentContext() error = %v", err)
+	}
+	if current != "dev" {
+		t.Errorf("GetCurrentContext() = %v, want dev", current)
+	}
+}
+
+func TestFragmentModeSwitchWritesOverlay(t *testing.T) {
+	fragmentsDir, cleanup := createTestFragments(t)
+	defer cleanup()
+
+	if err := SwitchContext("prod"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+
+	current, err := GetCurrentContext()
+	if err != nil {
+		t.Fatalf("GetCurrentContext() error = %v", err)
+	}
+	if current != "prod" {
+		t.Errorf("GetCurrentContext() = %v, want prod", current)
+	}
+
+	// The fragment itself must be left untouched
+	dev, err := clientcmd.LoadFromFile(filepath.Join(fragmentsDir, "a-dev.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load dev fragment: %v", err)
+	}
+	if dev.CurrentContext != "dev" {
+		t.Errorf("dev fragment current-context = %v, want dev", dev.CurrentContext)
+	}
+
+	overlay, err := clientcmd.LoadFromFile(GetOverlayPath())
+	if err != nil {
+		t.Fatalf("Failed to load overlay: %v", err)
+	}
+	if overlay.CurrentContext != "prod" {
+		t.Errorf("overlay current-context = %v, want prod", overlay.CurrentContext)
+	}
+	if len(overlay.Contexts) != 0 {
+		t.Errorf("overlay contexts count = %d, want 0", len(overlay.Contexts))
+	}
+}
+
+func TestFragmentModeSetNamespaceWritesOwningFragment(t *testing.T) {
+	fragmentsDir, cleanup := createTestFragments(t)
+	defer cleanup()
+
+	if err := SetNamespaceForContext("prod", "payments"); err != nil {
+		t.Fatalf("SetNamespaceForContext() error = %v", err)
+	}
+
+	prod, err := clientcmd.LoadFromFile(filepath.Join(fragmentsDir, "b-prod.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load prod fragment: %v", err)
+	}
+	if prod.Contexts["prod"].Namespace != "payments" {
+		t.Errorf("prod fragment namespace = %v, want payments", prod.Contexts["prod"].Namespace)
+	}
+	// The shadowed duplicate must not be overwritten with the winning entry
+	if prod.Contexts["dev"].Namespace != "shadowed" {
+		t.Errorf("shadowed dev namespace = %v, want shadowed", prod.Contexts["dev"].Namespace)
+	}
+}
+
+func TestFragmentModeDeleteContext(t *testing.T) {
+	fragmentsDir, cleanup := createTestFragments(t)
+	defer cleanup()
+
+	if err := DeleteContext("prod"); err != nil {
+		t.Fatalf("DeleteContext() error = %v", err)
+	}
+
+	prod, err := clientcmd.LoadFromFile(filepath.Join(fragmentsDir, "b-prod.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load prod fragment: %v", err)
+	}
+	if _, exists := prod.Contexts["prod"]; exists {
+		t.Errorf("context prod still exists in its fragment")
+	}
+	if _, exists := prod.Clusters["prod-cluster"]; !exists {
+		t.Errorf("prod-cluster removed while still referenced by the shadowed dev context")
+	}
+
+	contexts, err := GetContexts()
+	if err != nil {
+		t.Fatalf("GetContexts() error = %v", err)
+	}
+	if _, exists := contexts["prod"]; exists {
+		t.Errorf("GetContexts() still returns deleted context prod")
+	}
+}
+
+func TestSetFragmentsOverridesEnvironment(t *testing.T) {
+	fragmentsDir, cleanup := createTestFragments(t)
+	defer cleanup()
+	defer SetFragments("", "")
+
+	_ = os.Setenv(FragmentGlobEnv, "")
+	overlay := filepath.Join(t.TempDir(), "overlay.yaml")
+	SetFragments(filepath.Join(fragmentsDir, "*.yaml"), overlay)
+
+	if !IsFragmentMode() {
+		t.Fatal("IsFragmentMode() = false with a glob from SetFragments")
+	}
+	if got := GetOverlayPath(); got != overlay {
+		t.Errorf("GetOverlayPath() = %v, want %v", got, overlay)
+	}
+	contexts, err := GetContexts()
+	if err != nil {
+		t.Fatalf("GetContexts() error = %v", err)
+	}
+	if len(contexts) != 2 {
+		t.Errorf("GetContexts() count = %d, want 2", len(contexts))
+	}
+}
@@ -6,17 +6,15 @@
 // - Getting and setting the current context
 // - Managing namespaces
 // - Connecting to clusters
+// - Merging a directory of kubeconfig fragments
 package kubeconfig
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
-	"github.com/user-cube/kontext/pkg/static"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
@@ -33,56 +31,48 @@ func GetKubeConfigPath() string {
 }
 
 // GetKubeConfig loads the kubeconfig file
+//
+// In fragment mode (see SetFragments and FragmentGlobEnv) all fragments are loaded and merged.
 func GetKubeConfig() (*api.Config, error) {
+	if IsFragmentMode() {
+		return loadFragments()
+	}
+
 	configPath := GetKubeConfigPath()
 	config, err := clientcmd.LoadFromFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("error loading kubeconfig: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrKubeConfigLoad, err)
 	}
 	return config, nil
 }
 
+// writeKubeConfig saves the kubeconfig to the location it was loaded from
+//
+// In fragment mode changes are routed back to the fragment each entry came from.
+func writeKubeConfig(config *api.Config) error {
+	if IsFragmentMode() {
+		return writeFragments(config)
+	}
+
+	if err := clientcmd.WriteToFile(*config, GetKubeConfigPath()); err != nil {
+		return fmt.Errorf("%w: %w", ErrKubeConfigSave, err)
+	}
+	return nil
+}
+
 // GetContexts returns all available contexts in the kubeconfig
 func GetContexts() (map[string]*api.Context, error) {
-	config, err := GetKubeConfig()
-	if err != nil {
-		return nil, err
-	}
-	return config.Contexts, nil
+	return NewManager().GetContexts()
 }
 
 // GetCurrentContext returns the name of the current context
 func GetCurrentContext() (string, error) {
-	config, err := GetKubeConfig()
-	if err != nil {
-		return "", err
-	}
-	return config.CurrentContext, nil
+	return NewManager().GetCurrentContext()
 }
 
 // SwitchContext changes the current context to the specified one
 func SwitchContext(contextName string) error {
-	configPath := GetKubeConfigPath()
-	config, err := GetKubeConfig()
-	if err != nil {
-		return err
-	}
-
-	// Check if the context exists
-	if _, exists := config.Contexts[contextName]; !exists {
-		return fmt.Errorf("context '%s' does not exist", contextName)
-	}
-
-	// Set the current context
-	config.CurrentContext = contextName
-
-	// Save the updated config
-	err = clientcmd.WriteToFile(*config, configPath)
-	if err != nil {
-		return fmt.Errorf("error saving kubeconfig: %w", err)
-	}
-
-	return nil
+	return NewManager().SwitchContext(contextName)
 }
 
 // DeleteContext removes the specified context from the kubeconfig.
@@ -90,16 +80,32 @@ func SwitchContext(contextName string) error {
 // Any clusters or authInfos that are no longer referenced by any remaining context
 // will also be removed to keep the config clean.
 func DeleteContext(contextName string) error {
-	configPath := GetKubeConfigPath()
-	config, err := GetKubeConfig()
-	if err != nil {
-		return err
-	}
+	return NewManager().DeleteContext(contextName)
+}
 
-	// Check if the context exists
+// DeleteContexts removes several contexts from the kubeconfig with a single write
+//
+// It behaves like DeleteContext for each context. If any of the contexts does not
+// exist, nothing is deleted and an error is returned.
+func DeleteContexts(contextNames []string) error {
+	return NewManager().DeleteContexts(contextNames)
+}
+
+// RemoveEntries removes contexts, clusters and authInfos from the kubeconfig with a single write
+//
+// Contexts are removed like DeleteContext does, including the cleanup of clusters
+// and authInfos they leave unreferenced. The listed clusters and authInfos are
+// removed as well. If any listed entry does not exist, nothing is removed.
+func RemoveEntries(contextNames, clusterNames, authInfoNames []string) error {
+	return NewManager().RemoveEntries(contextNames, clusterNames, authInfoNames)
+}
+
+// deleteContext removes a context from the config along with the cluster and
+// authInfo it leaves unreferenced
+func deleteContext(config *api.Config, contextName string) {
 	ctx, exists := config.Contexts[contextName]
 	if !exists {
-		return fmt.Errorf("context '%s' does not exist", contextName)
+		return
 	}
 
 	// Track associated cluster and auth info so we can clean them up if unused
@@ -114,132 +120,80 @@ func DeleteContext(contextName string) error {
 		config.CurrentContext = ""
 	}
 
-	// Helper to check if a cluster/authInfo is still referenced by any context
-	isClusterReferenced := func(name string) bool {
-		if name == "" {
-			return false
-		}
-		for _, c := range config.Contexts {
-			if c != nil && c.Cluster == name {
-				return true
-			}
-		}
-		return false
-	}
-
-	isAuthInfoReferenced := func(name string) bool {
-		if name == "" {
-			return false
-		}
-		for _, c := range config.Contexts {
-			if c != nil && c.AuthInfo == name {
-				return true
-			}
-		}
-		return false
-	}
-
 	// Clean up cluster if no longer referenced
-	if clusterName != "" && !isClusterReferenced(clusterName) {
+	if clusterName != "" && !isClusterReferenced(config, clusterName) {
 		delete(config.Clusters, clusterName)
 	}
 
 	// Clean up authInfo if no longer referenced
-	if authInfoName != "" && !isAuthInfoReferenced(authInfoName) {
+	if authInfoName != "" && !isAuthInfoReferenced(config, authInfoName) {
 		delete(config.AuthInfos, authInfoName)
 	}
-
-	// Save the updated config
-	if err := clientcmd.WriteToFile(*config, configPath); err != nil {
-		return fmt.Errorf("error saving kubeconfig: %w", err)
-	}
-
-	return nil
 }
 
-// GetCurrentNamespace returns the namespace set for the current context
-func GetCurrentNamespace() (string, error) {
-	config, err := GetKubeConfig()
-	if err != nil {
-		return "", err
+// isClusterReferenced checks if a cluster is still referenced by any context
+func isClusterReferenced(config *api.Config, name string) bool {
+	for _, c := range config.Contexts {
+		if c != nil && c.Cluster == name {
+			return true
+		}
 	}
+	return false
+}
 
-	currentContext := config.CurrentContext
-	if currentContext == "" {
-		return "", fmt.Errorf("no current context set")
+// isAuthInfoReferenced checks if an authInfo is still referenced by any context
+func isAuthInfoReferenced(config *api.Config, name string) bool {
+	for _, c := range config.Contexts {
+		if c != nil && c.AuthInfo == name {
+			return true
+		}
 	}
+	return false
+}
 
-	context, exists := config.Contexts[currentContext]
-	if !exists {
-		return "", fmt.Errorf("current context '%s' does not exist in config", currentContext)
+// OrphanClusters returns the sorted names of clusters not referenced by any context
+func OrphanClusters(config *api.Config) []string {
+	var orphans []string
+	for name := range config.Clusters {
+		if !isClusterReferenced(config, name) {
+			orphans = append(orphans, name)
+		}
 	}
+	sort.Strings(orphans)
+	return orphans
+}
 
-	// If namespace is empty, return "default"
-	if context.Namespace == "" {
-		return "default", nil
+// OrphanAuthInfos returns the sorted names of authInfos not referenced by any context
+func OrphanAuthInfos(config *api.Config) []string {
+	var orphans []string
+	for name := range config.AuthInfos {
+		if !isAuthInfoReferenced(config, name) {
+			orphans = append(orphans, name)
+		}
 	}
+	sort.Strings(orphans)
+	return orphans
+}
 
-	return context.Namespace, nil
+// GetCurrentNamespace returns the namespace set for the current context
+func GetCurrentNamespace() (string, error) {
+	return NewManager().GetCurrentNamespace()
 }
 
 // GetNamespaceForContext returns the namespace for the specified context
 func GetNamespaceForContext(contextName string) (string, error) {
-	config, err := GetKubeConfig()
-	if err != nil {
-		return "", err
-	}
-
-	context, exists := config.Contexts[contextName]
-	if !exists {
-		return "", fmt.Errorf("context '%s' does not exist", contextName)
-	}
-
-	// If namespace is empty, return "default"
-	if context.Namespace == "" {
-		return "default", nil
-	}
-
-	return context.Namespace, nil
+	return NewManager().GetNamespaceForContext(contextName)
 }
 
 // SetNamespace sets the namespace for the current context
 func SetNamespace(namespace string) error {
-	return SetNamespaceForContext("", namespace)
+	return NewManager().SetNamespace(namespace)
 }
 
 // SetNamespaceForContext sets the namespace for the specified context
 // If contextName is empty, it uses the current context
 func SetNamespaceForContext(contextName string, namespace string) error {
-	configPath := GetKubeConfigPath()
-	config, err := GetKubeConfig()
-	if err != nil {
-		return err
-	}
-
-	// Use current context if none specified
-	if contextName == "" {
-		contextName = config.CurrentContext
-		if contextName == "" {
-			return fmt.Errorf("no current context set")
-		}
-	}
-
-	// Check if the context exists
-	context, exists := config.Contexts[contextName]
-	if !exists {
-		return fmt.Errorf("context '%s' does not exist", contextName)
-	}
-
-	// Set the namespace
-	context.Namespace = namespace
-
-	// Save the updated config
-	err = clientcmd.WriteToFile(*config, configPath)
-	if err != nil {
-		return fmt.Errorf("error saving kubeconfig: %w", err)
-	}
-
-	return nil
+	return NewManager().SetNamespaceForContext(contextName, namespace)
 }
 
 // GetNamespaces returns all available namespaces for the current context
@@ -247,65 +201,86 @@ func SetNamespaceForContext(contextName string, namespace string) error {
 // This function attempts to connect to the cluster and list namespaces.
 // If the connection fails, it returns a set of default namespaces.
 func GetNamespaces() ([]string, error) {
-	return GetNamespacesForContext("")
+	return NewManager().GetNamespaces()
 }
 
 // GetNamespacesForContext returns all available namespaces for the specified context
 // If contextName is empty, it uses the current context
 func GetNamespacesForContext(contextName string) ([]string, error) {
-	config, err := GetKubeConfig()
-	if err != nil {
-		return nil, err
-	}
+	return NewManager().GetNamespacesForContext(contextName)
+}
+
+// ListNamespacesForContext returns the namespaces of the specified context's cluster
+//
+// Unlike GetNamespacesForContext it does not fall back to default namespaces:
+// connection failures are returned wrapped in ErrClusterUnreachable and API
+// errors (for example RBAC denials) are returned as-is.
+// If contextName is empty, it uses the current context.
+func ListNamespacesForContext(contextName string) ([]string, error) {
+	return NewManager().ListNamespacesForContext(contextName)
+}
+
+// CreateNamespaceForContext creates a namespace with labels in the specified context's cluster
+// If contextName is empty, it uses the current context.
+func CreateNamespaceForContext(contextName, namespace string, labels map[string]string) error {
+	return NewManager().CreateNamespaceForContext(contextName, namespace, labels)
+}
 
+// resolveContextName returns contextName, or the current context if it is empty,
+// after checking that the context exists
+func resolveContextName(config *api.Config, contextName string) (string, error) {
 	// Use current context if none specified
 	if contextName == "" {
 		contextName = config.CurrentContext
 		if contextName == "" {
-			return nil, fmt.Errorf("no current context set")
+			return "", ErrNoCurrentContext
 		}
 	}
 
 	// Check if the context exists
-	_, exists := config.Contexts[contextName]
-	if !exists {
-		return nil, fmt.Errorf("context '%s' does not exist", contextName)
-	}
-
-	// Create client configuration for the specified context
-	clientConfig := clientcmd.NewNonInteractiveClientConfig(
-		*config,
-		contextName,
-		&clientcmd.ConfigOverrides{},
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-	)
-
-	// Get REST config for the context
-	restConfig, err := clientConfig.ClientConfig()
-	if err != nil {
-		// If we can't connect to the cluster, return some default namespaces
-		// This handles the case where the user might be offline or the cluster is unavailable
-		return static.FallBackNamespace, nil
+	if _, exists := config.Contexts[contextName]; !exists {
+		return "", notFound(ErrContextNotFound, contextName)
 	}
 
-	// Create the clientset
-	clientset, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return static.FallBackNamespace, nil
-	}
+	return contextName, nil
+}
 
-	// Try to list namespaces from the cluster
-	namespaceList, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		// Fall back to default namespaces if there's an error
-		return static.FallBackNamespace, nil
+// DeletionPlan returns the clusters and authInfos that would be removed along
+// with the given contexts because no remaining context references them
+func DeletionPlan(config *api.Config, contextNames []string) (clusters []string, authInfos []string) {
+	deleting := make(map[string]bool, len(contextNames))
+	for _, name := range contextNames {
+		deleting[name] = true
+	}
+
+	clusterSeen := make(map[string]bool)
+	authInfoSeen := make(map[string]bool)
+	remainingClusters := make(map[string]bool)
+	remainingAuthInfos := make(map[string]bool)
+	for name, ctx := range config.Contexts {
+		if ctx == nil || deleting[name] {
+			continue
+		}
+		remainingClusters[ctx.Cluster] = true
+		remainingAuthInfos[ctx.AuthInfo] = true
 	}
 
-	// Extract namespace names from the response
-	namespaces := make([]string, 0, len(namespaceList.Items))
-	for _, ns := range namespaceList.Items {
-		namespaces = append(namespaces, ns.Name)
+	for _, name := range contextNames {
+		ctx, exists := config.Contexts[name]
+		if !exists || ctx == nil {
+			continue
+		}
+		if _, exists := config.Clusters[ctx.Cluster]; exists && !remainingClusters[ctx.Cluster] && !clusterSeen[ctx.Cluster] {
+			clusterSeen[ctx.Cluster] = true
+			clusters = append(clusters, ctx.Cluster)
+		}
+		if _, exists := config.AuthInfos[ctx.AuthInfo]; exists && !remainingAuthInfos[ctx.AuthInfo] && !authInfoSeen[ctx.AuthInfo] {
+			authInfoSeen[ctx.AuthInfo] = true
+			authInfos = append(authInfos, ctx.AuthInfo)
+		}
 	}
 
-	return namespaces, nil
+	sort.Strings(clusters)
+	sort.Strings(authInfos)
+	return clusters, authInfos
 }
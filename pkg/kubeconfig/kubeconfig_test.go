@@ -1,6 +1,7 @@
 package kubeconfig
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -600,3 +601,165 @@ func TestSetNamespaceForContext(t *testing.T) {
 		})
 	}
 }
+
+func TestDeletionPlan(t *testing.T) {
+	configPath, config := createTestKubeConfig(t)
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(configPath))
+	}()
+
+	tests := []struct {
+		name          string
+		contexts      []string
+		wantClusters  []string
+		wantAuthInfos []string
+	}{
+		{
+			name:     "Shared cluster and authInfo are kept",
+			contexts: []string{"context1"},
+		},
+		{
+			name:          "Unshared cluster and authInfo are removed",
+			contexts:      []string{"context2"},
+			wantClusters:  []string{"cluster2"},
+			wantAuthInfos: []string{"user2"},
+		},
+		{
+			name:          "Removing every user of a shared cluster removes it",
+			contexts:      []string{"context1", "context3"},
+			wantClusters:  []string{"cluster1"},
+			wantAuthInfos: []string{"user1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters, authInfos := DeletionPlan(config, tt.contexts)
+			if len(clusters) != len(tt.wantClusters) || (len(clusters) > 0 && clusters[0] != tt.wantClusters[0]) {
+				t.Errorf("DeletionPlan() clusters = %v, want %v", clusters, tt.wantClusters)
+			}
+			if len(authInfos) != len(tt.wantAuthInfos) || (len(authInfos) > 0 && authInfos[0] != tt.wantAuthInfos[0]) {
+				t.Errorf("DeletionPlan() authInfos = %v, want %v", authInfos, tt.wantAuthInfos)
+			}
+		})
+	}
+}
+
+func TestDeleteContexts(t *testing.T) {
+	configPath, _ := createTestKubeConfig(t)
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(configPath))
+	}()
+
+	originalEnv := os.Getenv("KUBECONFIG")
+	defer func() {
+		_ = os.Setenv("KUBECONFIG", originalEnv)
+	}()
+
+	_ = os.Setenv("KUBECONFIG", configPath)
+
+	// A missing context aborts the whole deletion
+	if err := DeleteContexts([]string{"context2", "nonexistent"}); err == nil {
+		t.Fatal("DeleteContexts() with a missing context should fail")
+	}
+	contexts, err := GetContexts()
+	if err != nil {
+		t.Fatalf("GetContexts() error = %v", err)
+	}
+	if len(contexts) != 3 {
+		t.Fatalf("DeleteContexts() partially deleted contexts, count = %d, want 3", len(contexts))
+	}
+
+	if err := DeleteContexts([]string{"context1", "context3"}); err != nil {
+		t.Fatalf("DeleteContexts() error = %v", err)
+	}
+
+	config, err := GetKubeConfig()
+	if err != nil {
+		t.Fatalf("GetKubeConfig() error = %v", err)
+	}
+	if len(config.Contexts) != 1 {
+		t.Errorf("Contexts count = %d, want 1", len(config.Contexts))
+	}
+	if _, exists := config.Clusters["cluster1"]; exists {
+		t.Errorf("cluster1 should be removed once no context references it")
+	}
+	if config.CurrentContext != "" {
+		t.Errorf("CurrentContext = %v, want unset", config.CurrentContext)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	configPath, _ := createTestKubeConfig(t)
+	defer func() { _ = os.RemoveAll(filepath.Dir(configPath)) }()
+
+	originalEnv := os.Getenv("KUBECONFIG")
+	defer func() {
+		_ = os.Setenv("KUBECONFIG", originalEnv)
+	}()
+
+	_ = os.Setenv("KUBECONFIG", configPath)
+
+	tests := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{
+			name: "Switch to a missing context",
+			run:  func() error { return SwitchContext("nonexistent") },
+			want: ErrContextNotFound,
+		},
+		{
+			name: "Remove a missing cluster",
+			run:  func() error { return RemoveEntries(nil, []string{"nonexistent"}, nil) },
+			want: ErrClusterNotFound,
+		},
+		{
+			name: "Remove a missing user",
+			run:  func() error { return RemoveEntries(nil, nil, []string{"nonexistent"}) },
+			want: ErrUserNotFound,
+		},
+		{
+			name: "Pattern without matches",
+			run: func() error {
+				_, err := MatchContexts([]string{"context1"}, []string{"pr-*"}, false)
+				return err
+			},
+			want: ErrNoMatchingContexts,
+		},
+		{
+			name: "No current context",
+			run: func() error {
+				config, err := GetKubeConfig()
+				if err != nil {
+					return err
+				}
+				config.CurrentContext = ""
+				if err := writeKubeConfig(config); err != nil {
+					return err
+				}
+				_, err = GetCurrentNamespace()
+				return err
+			},
+			want: ErrNoCurrentContext,
+		},
+		{
+			name: "Missing kubeconfig file",
+			run: func() error {
+				_ = os.Setenv("KUBECONFIG", filepath.Join(filepath.Dir(configPath), "missing"))
+				_, err := GetKubeConfig()
+				return err
+			},
+			want: ErrKubeConfigLoad,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
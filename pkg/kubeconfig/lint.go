@@ -0,0 +1,280 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Severity describes how serious a lint issue is
+type Severity string
+
+const (
+	// SeverityError marks entries that are broken and will fail when used
+	SeverityError Severity = "error"
+	// SeverityWarning marks entries that work but are unsafe or untidy
+	SeverityWarning Severity = "warning"
+	// SeverityInfo marks entries that are worth knowing about
+	SeverityInfo Severity = "info"
+)
+
+// Issue codes reported by Lint
+const (
+	IssueMissingCluster        = "context-missing-cluster"
+	IssueMissingUser           = "context-missing-user"
+	IssueOrphanCluster         = "orphan-cluster"
+	IssueOrphanUser            = "orphan-user"
+	IssueDuplicateServer       = "duplicate-server"
+	IssueMissingFile           = "missing-file"
+	IssueExecNotFound          = "exec-command-not-found"
+	IssueInsecureSkipTLSVerify = "insecure-skip-tls-verify"
+	IssueMissingCurrentContext = "current-context-missing"
+)
+
+// Issue is a single problem found in a kubeconfig
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Message  string   `json:"message"`
+	Fixable  bool     `json:"fixable"`
+}
+
+// Lint validates a kubeconfig and returns the issues found
+//
+// Issues are sorted by severity, then by kind and name, so the output is stable.
+func Lint(config *api.Config) []Issue {
+	var issues []Issue
+
+	for name, ctx := range config.Contexts {
+		if ctx == nil {
+			continue
+		}
+		if _, exists := config.Clusters[ctx.Cluster]; !exists {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     IssueMissingCluster,
+				Kind:     "context",
+				Name:     name,
+				Message:  fmt.Sprintf("references cluster '%s' which does not exist", ctx.Cluster),
+			})
+		}
+		if ctx.AuthInfo != "" {
+			if _, exists := config.AuthInfos[ctx.AuthInfo]; !exists {
+				issues = append(issues, Issue{
+					Severity: SeverityError,
+					Code:     IssueMissingUser,
+					Kind:     "context",
+					Name:     name,
+					Message:  fmt.Sprintf("references user '%s' which does not exist", ctx.AuthInfo),
+				})
+			}
+		}
+	}
+
+	servers := make(map[string][]string)
+	for name, cluster := range config.Clusters {
+		if cluster == nil {
+			continue
+		}
+		if !isClusterReferenced(config, name) {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     IssueOrphanCluster,
+				Kind:     "cluster",
+				Name:     name,
+				Message:  "is not referenced by any context",
+				Fixable:  true,
+			})
+		}
+		if cluster.InsecureSkipTLSVerify {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     IssueInsecureSkipTLSVerify,
+				Kind:     "cluster",
+				Name:     name,
+				Message:  "disables TLS certificate verification",
+			})
+		}
+		if cluster.CertificateAuthority != "" {
+			issues = append(issues, checkFile("cluster", name, "certificate-authority", cluster.CertificateAuthority, cluster.LocationOfOrigin)...)
+		}
+		if cluster.Server != "" {
+			servers[cluster.Server] = append(servers[cluster.Server], name)
+		}
+	}
+
+	for server, names := range servers {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			issues = append(issues, Issue{
+				Severity: SeverityInfo,
+				Code:     IssueDuplicateServer,
+				Kind:     "cluster",
+				Name:     name,
+				Message:  fmt.Sprintf("shares server %s with %s", server, strings.Join(without(names, name), ", ")),
+			})
+		}
+	}
+
+	for name, authInfo := range config.AuthInfos {
+		if authInfo == nil {
+			continue
+		}
+		if !isAuthInfoReferenced(config, name) {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     IssueOrphanUser,
+				Kind:     "user",
+				Name:     name,
+				Message:  "is not referenced by any context",
+				Fixable:  true,
+			})
+		}
+		if authInfo.ClientCertificate != "" {
+			issues = append(issues, checkFile("user", name, "client-certificate", authInfo.ClientCertificate, authInfo.LocationOfOrigin)...)
+		}
+		if authInfo.ClientKey != "" {
+			issues = append(issues, checkFile("user", name, "client-key", authInfo.ClientKey, authInfo.LocationOfOrigin)...)
+		}
+		if authInfo.TokenFile != "" {
+			issues = append(issues, checkFile("user", name, "tokenFile", authInfo.TokenFile, authInfo.LocationOfOrigin)...)
+		}
+		if authInfo.Exec != nil && authInfo.Exec.Command != "" {
+			if !commandExists(authInfo.Exec.Command, authInfo.LocationOfOrigin) {
+				issues = append(issues, Issue{
+					Severity: SeverityError,
+					Code:     IssueExecNotFound,
+					Kind:     "user",
+					Name:     name,
+					Message:  fmt.Sprintf("exec plugin command '%s' was not found on PATH", authInfo.Exec.Command),
+				})
+			}
+		}
+	}
+
+	if config.CurrentContext != "" {
+		if _, exists := config.Contexts[config.CurrentContext]; !exists {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     IssueMissingCurrentContext,
+				Kind:     "kubeconfig",
+				Name:     "current-context",
+				Message:  fmt.Sprintf("is set to '%s' which does not exist", config.CurrentContext),
+				Fixable:  true,
+			})
+		}
+	}
+
+	sortIssues(issues)
+	return issues
+}
+
+// FixIssues applies the safe repairs for the fixable issues to the config
+// and returns the issues that were fixed
+//
+// Safe repairs are removing orphan clusters and users and unsetting a
+// current-context that does not exist. Nothing that is referenced is removed.
+func FixIssues(config *api.Config, issues []Issue) []Issue {
+	var fixed []Issue
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+		switch issue.Code {
+		case IssueOrphanCluster:
+			delete(config.Clusters, issue.Name)
+		case IssueOrphanUser:
+			delete(config.AuthInfos, issue.Name)
+		case IssueMissingCurrentContext:
+			config.CurrentContext = ""
+		default:
+			continue
+		}
+		fixed = append(fixed, issue)
+	}
+	return fixed
+}
+
+// LintKubeConfig loads the kubeconfig and validates it
+func LintKubeConfig() ([]Issue, error) {
+	return NewManager().Lint()
+}
+
+// FixKubeConfig loads the kubeconfig, applies all safe repairs and saves it
+// It returns the issues that were fixed.
+func FixKubeConfig() ([]Issue, error) {
+	return NewManager().Fix()
+}
+
+// checkFile reports a missing-file issue if a file referenced by an entry does not exist
+// Relative paths are resolved against the directory of the file the entry came from.
+func checkFile(kind, name, field, path, origin string) []Issue {
+	resolved := path
+	if !filepath.IsAbs(path) && origin != "" {
+		resolved = filepath.Join(filepath.Dir(origin), path)
+	}
+
+	if _, err := os.Stat(resolved); err == nil {
+		return nil
+	}
+
+	return []Issue{{
+		Severity: SeverityError,
+		Code:     IssueMissingFile,
+		Kind:     kind,
+		Name:     name,
+		Message:  fmt.Sprintf("%s file %s does not exist", field, path),
+	}}
+}
+
+// commandExists reports whether an exec plugin command can be run
+func commandExists(command, origin string) bool {
+	if strings.ContainsRune(command, filepath.Separator) {
+		if !filepath.IsAbs(command) && origin != "" {
+			command = filepath.Join(filepath.Dir(origin), command)
+		}
+		_, err := os.Stat(command)
+		return err == nil
+	}
+	_, err := exec.LookPath(command)
+	return err == nil
+}
+
+// sortIssues orders issues by severity, kind, name and code
+func sortIssues(issues []Issue) {
+	rank := map[Severity]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if rank[a.Severity] != rank[b.Severity] {
+			return rank[a.Severity] < rank[b.Severity]
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Code < b.Code
+	})
+}
+
+// without returns a copy of names with the given name removed
+func without(names []string, name string) []string {
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// findIssue returns the first issue with the given code and name
+func findIssue(issues []Issue, code, name string) *Issue {
+	for i := range issues {
+		if issues[i].Code == code && issues[i].Name == name {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func TestLint(t *testing.T) {
+	config := api.NewConfig()
+	config.Clusters["cluster1"] = &api.Cluster{Server: "https://shared.example.com"}
+	config.Clusters["cluster2"] = &api.Cluster{Server: "https://shared.example.com", InsecureSkipTLSVerify: true}
+	config.Clusters["orphan"] = &api.Cluster{Server: "https://orphan.example.com"}
+	config.Clusters["with-ca"] = &api.Cluster{Server: "https://ca.example.com", CertificateAuthority: "/nonexistent/ca.crt"}
+	config.AuthInfos["user1"] = &api.AuthInfo{Token: "token"}
+	config.AuthInfos["orphan-user"] = &api.AuthInfo{Token: "token"}
+	config.AuthInfos["exec-user"] = &api.AuthInfo{Exec: &api.ExecConfig{Command: "kontext-nonexistent-plugin"}}
+	config.Contexts["good"] = &api.Context{Cluster: "cluster1", AuthInfo: "user1"}
+	config.Contexts["insecure"] = &api.Context{Cluster: "cluster2", AuthInfo: "exec-user"}
+	config.Contexts["ca"] = &api.Context{Cluster: "with-ca", AuthInfo: "user1"}
+	config.Contexts["broken"] = &api.Context{Cluster: "missing", AuthInfo: "missing-user"}
+	config.CurrentContext = "gone"
+
+	issues := Lint(config)
+
+	tests := []struct {
+		code     string
+		name     string
+		severity Severity
+		fixable  bool
+	}{
+		{IssueMissingCluster, "broken", SeverityError, false},
+		{IssueMissingUser, "broken", SeverityError, false},
+		{IssueOrphanCluster, "orphan", SeverityWarning, true},
+		{IssueOrphanUser, "orphan-user", SeverityWarning, true},
+		{IssueDuplicateServer, "cluster1", SeverityInfo, false},
+		{IssueDuplicateServer, "cluster2", SeverityInfo, false},
+		{IssueMissingFile, "with-ca", SeverityError, false},
+		{IssueExecNotFound, "exec-user", SeverityError, false},
+		{IssueInsecureSkipTLSVerify, "cluster2", SeverityWarning, false},
+		{IssueMissingCurrentContext, "current-context", SeverityError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.name, func(t *testing.T) {
+			issue := findIssue(issues, tt.code, tt.name)
+			if issue == nil {
+				t.Fatalf("Lint() missing issue %s for %s", tt.code, tt.name)
+			}
+			if issue.Severity != tt.severity {
+				t.Errorf("Lint() severity = %v, want %v", issue.Severity, tt.severity)
+			}
+			if issue.Fixable != tt.fixable {
+				t.Errorf("Lint() fixable = %v, want %v", issue.Fixable, tt.fixable)
+			}
+		})
+	}
+
+	if len(issues) != len(tests) {
+		t.Errorf("Lint() returned %d issues, want %d: %+v", len(issues), len(tests), issues)
+	}
+
+	// Errors are always reported first
+	if issues[0].Severity != SeverityError {
+		t.Errorf("Lint() first issue severity = %v, want error", issues[0].Severity)
+	}
+}
+
+func TestLintValidConfig(t *testing.T) {
+	configPath, _ := createTestKubeConfig(t)
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(configPath))
+	}()
+
+	originalEnv := os.Getenv("KUBECONFIG")
+	defer func() {
+		_ = os.Setenv("KUBECONFIG", originalEnv)
+	}()
+
+	_ = os.Setenv("KUBECONFIG", configPath)
+
+	issues, err := LintKubeConfig()
+	if err != nil {
+		t.Fatalf("LintKubeConfig() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("LintKubeConfig() returned %d issues, want 0: %+v", len(issues), issues)
+	}
+}
+
+func TestFixKubeConfig(t *testing.T) {
+	configPath, config := createTestKubeConfig(t)
+	defer func() {
+		_ = os.RemoveAll(filepath.Dir(configPath))
+	}()
+
+	originalEnv := os.Getenv("KUBECONFIG")
+	defer func() {
+		_ = os.Setenv("KUBECONFIG", originalEnv)
+	}()
+
+	_ = os.Setenv("KUBECONFIG", configPath)
+
+	// Add an orphan cluster and a dangling current context
+	config.Clusters["orphan"] = &api.Cluster{Server: "https://orphan.example.com"}
+	config.CurrentContext = "gone"
+	if err := clientcmd.WriteToFile(*config, configPath); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	fixed, err := FixKubeConfig()
+	if err != nil {
+		t.Fatalf("FixKubeConfig() error = %v", err)
+	}
+	if len(fixed) != 2 {
+		t.Errorf("FixKubeConfig() fixed %d issues, want 2", len(fixed))
+	}
+
+	issues, err := LintKubeConfig()
+	if err != nil {
+		t.Fatalf("LintKubeConfig() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("LintKubeConfig() after fix returned %d issues, want 0: %+v", len(issues), issues)
+	}
+
+	contexts, err := GetContexts()
+	if err != nil {
+		t.Fatalf("GetContexts() error = %v", err)
+	}
+	if len(contexts) != 3 {
+		t.Errorf("FixKubeConfig() removed contexts, count = %d, want 3", len(contexts))
+	}
+}
@@ -0,0 +1,576 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user-cube/kontext/pkg/static"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Writer saves a kubeconfig after it was changed
+type Writer interface {
+	Write(config *api.Config) error
+}
+
+// WriterFunc adapts an ordinary function to the Writer interface
+type WriterFunc func(config *api.Config) error
+
+// Write implements Writer
+func (f WriterFunc) Write(config *api.Config) error {
+	return f(config)
+}
+
+// Clock returns the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock backed by time.Now
+type realClock struct{}
+
+// Now implements Clock
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Logf prints a debug message
+type Logf func(format string, args ...interface{})
+
+// defaultLogf is the Logf of managers created without WithLogf
+var defaultLogf Logf
+
+// SetLogf sets the Logf used by managers created without WithLogf,
+// including the ones behind the package-level functions
+// A nil Logf disables debug messages.
+func SetLogf(logf Logf) {
+	defaultLogf = logf
+}
+
+// Manager gives access to a kubeconfig and its clusters
+//
+// A Manager loads its kubeconfig once, on first use, and keeps it in memory.
+// Operations that change the kubeconfig update the in-memory copy and then
+// save it with the manager's Writer.
+//
+// By default the kubeconfig comes from the environment (KUBECONFIG, HOME and
+// fragment mode, see GetKubeConfig) and clusters are reached with client-go.
+// Use the options to load specific files or an in-memory config, and to
+// replace the writer, the cluster client or the clock, for example when
+// embedding kontext in other tools or in tests.
+//
+// A Manager is not safe for concurrent use, except that read-only operations
+// such as ProbeContext may run concurrently once Config has loaded the kubeconfig.
+type Manager struct {
+	load       func() (*api.Config, error)
+	sourceSave Writer
+	writer     Writer
+	client     ClusterClient
+	clock      Clock
+	logf       Logf
+	timeout    time.Duration
+	fallback   []string
+
+	config *api.Config
+}
+
+// Option configures a Manager
+type Option func(*Manager)
+
+// WithPath loads the kubeconfig from a single file and saves changes back to it
+func WithPath(path string) Option {
+	return func(m *Manager) {
+		m.load = func() (*api.Config, error) {
+			config, err := clientcmd.LoadFromFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrKubeConfigLoad, err)
+			}
+			return config, nil
+		}
+		m.sourceSave = WriterFunc(func(config *api.Config) error {
+			if err := clientcmd.WriteToFile(*config, path); err != nil {
+				return fmt.Errorf("%w: %w", ErrKubeConfigSave, err)
+			}
+			return nil
+		})
+	}
+}
+
+// WithFragments loads and merges several kubeconfig files and an overlay file,
+// like fragment mode does, and routes changes back to the file each entry
+// came from. The current context is stored in the overlay.
+func WithFragments(paths []string, overlayPath string) Option {
+	return func(m *Manager) {
+		m.load = func() (*api.Config, error) {
+			return loadFragmentFiles(paths, overlayPath)
+		}
+		m.sourceSave = WriterFunc(func(config *api.Config) error {
+			return writeFragmentFiles(config, paths, overlayPath)
+		})
+	}
+}
+
+// WithConfig uses an in-memory kubeconfig
+// Changes are kept in memory unless a Writer is set with WithWriter.
+func WithConfig(config *api.Config) Option {
+	return func(m *Manager) {
+		m.load = func() (*api.Config, error) {
+			return config, nil
+		}
+		m.sourceSave = nil
+	}
+}
+
+// WithWriter saves changes with w instead of writing them back to their source
+func WithWriter(w Writer) Option {
+	return func(m *Manager) {
+		m.writer = w
+	}
+}
+
+// WithClusterClient uses c to talk to clusters instead of client-go
+func WithClusterClient(c ClusterClient) Option {
+	return func(m *Manager) {
+		m.client = c
+	}
+}
+
+// WithClock uses c as the source of the current time
+func WithClock(c Clock) Option {
+	return func(m *Manager) {
+		m.clock = c
+	}
+}
+
+// WithRequestTimeout limits API calls of the default cluster client, such as
+// listing namespaces. Zero keeps the client-go default. It has no effect with
+// WithClusterClient.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.timeout = timeout
+	}
+}
+
+// WithFallbackNamespaces sets the namespaces GetNamespacesForContext returns
+// when a cluster can't be queried, instead of the built-in defaults
+func WithFallbackNamespaces(namespaces []string) Option {
+	return func(m *Manager) {
+		m.fallback = namespaces
+	}
+}
+
+// WithLogf reports which files were loaded and how long cluster calls took to logf
+func WithLogf(logf Logf) Option {
+	return func(m *Manager) {
+		m.logf = logf
+	}
+}
+
+// NewManager creates a Manager
+// Without options it behaves like the package-level functions.
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{
+		load:       GetKubeConfig,
+		sourceSave: WriterFunc(writeKubeConfig),
+		clock:      realClock{},
+		logf:       defaultLogf,
+		fallback:   static.FallBackNamespace,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.writer == nil {
+		m.writer = m.sourceSave
+	}
+	if m.client == nil {
+		m.client = kubeClusterClient{timeout: m.timeout}
+	}
+	return m
+}
+
+// Config returns the kubeconfig, loading it on first use
+// The returned config is shared with the manager; changes to it are saved by Save.
+func (m *Manager) Config() (*api.Config, error) {
+	if m.config != nil {
+		return m.config, nil
+	}
+
+	start := time.Now()
+	config, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	m.config = config
+	m.debugf("loaded kubeconfig in %s from %s", time.Since(start).Round(time.Millisecond), strings.Join(sourceFiles(config), ", "))
+	return config, nil
+}
+
+// debugf prints a debug message with the manager's Logf, if any
+func (m *Manager) debugf(format string, args ...interface{}) {
+	if m.logf != nil {
+		m.logf(format, args...)
+	}
+}
+
+// sourceFiles returns the files the entries of a config were loaded from
+func sourceFiles(config *api.Config) []string {
+	seen := map[string]bool{}
+	add := func(path string) {
+		if path != "" {
+			seen[path] = true
+		}
+	}
+	for _, context := range config.Contexts {
+		add(context.LocationOfOrigin)
+	}
+	for _, cluster := range config.Clusters {
+		add(cluster.LocationOfOrigin)
+	}
+	for _, authInfo := range config.AuthInfos {
+		add(authInfo.LocationOfOrigin)
+	}
+
+	if len(seen) == 0 {
+		return []string{"memory"}
+	}
+	files := make([]string, 0, len(seen))
+	for path := range seen {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Save writes the in-memory kubeconfig with the manager's Writer
+// It does nothing for an in-memory config without a Writer.
+func (m *Manager) Save() error {
+	if m.config == nil || m.writer == nil {
+		return nil
+	}
+
+	start := time.Now()
+	if err := m.writer.Write(m.config); err != nil {
+		return err
+	}
+	m.debugf("saved kubeconfig in %s", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// Now returns the current time according to the manager's clock
+func (m *Manager) Now() time.Time {
+	return m.clock.Now()
+}
+
+// GetContexts returns all available contexts in the kubeconfig
+func (m *Manager) GetContexts() (map[string]*api.Context, error) {
+	config, err := m.Config()
+	if err != nil {
+		return nil, err
+	}
+	return config.Contexts, nil
+}
+
+// GetCurrentContext returns the name of the current context
+func (m *Manager) GetCurrentContext() (string, error) {
+	config, err := m.Config()
+	if err != nil {
+		return "", err
+	}
+	return config.CurrentContext, nil
+}
+
+// SwitchContext changes the current context to the specified one
+func (m *Manager) SwitchContext(contextName string) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	// Check if the context exists
+	if _, exists := config.Contexts[contextName]; !exists {
+		return notFound(ErrContextNotFound, contextName)
+	}
+
+	// Set the current context
+	config.CurrentContext = contextName
+
+	// Save the updated config
+	return m.Save()
+}
+
+// DeleteContext removes the specified context from the kubeconfig.
+// If the deleted context is the current context, the current context will be unset.
+// Any clusters or authInfos that are no longer referenced by any remaining context
+// will also be removed to keep the config clean.
+func (m *Manager) DeleteContext(contextName string) error {
+	return m.DeleteContexts([]string{contextName})
+}
+
+// DeleteContexts removes several contexts from the kubeconfig with a single write
+//
+// It behaves like DeleteContext for each context. If any of the contexts does not
+// exist, nothing is deleted and an error is returned.
+func (m *Manager) DeleteContexts(contextNames []string) error {
+	return m.RemoveEntries(contextNames, nil, nil)
+}
+
+// RemoveEntries removes contexts, clusters and authInfos from the kubeconfig with a single write
+//
+// Contexts are removed like DeleteContext does, including the cleanup of clusters
+// and authInfos they leave unreferenced. The listed clusters and authInfos are
+// removed as well. If any listed entry does not exist, nothing is removed.
+func (m *Manager) RemoveEntries(contextNames, clusterNames, authInfoNames []string) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	// Check that every entry exists before changing anything
+	for _, name := range contextNames {
+		if _, exists := config.Contexts[name]; !exists {
+			return notFound(ErrContextNotFound, name)
+		}
+	}
+	for _, name := range clusterNames {
+		if _, exists := config.Clusters[name]; !exists {
+			return notFound(ErrClusterNotFound, name)
+		}
+	}
+	for _, name := range authInfoNames {
+		if _, exists := config.AuthInfos[name]; !exists {
+			return notFound(ErrUserNotFound, name)
+		}
+	}
+
+	for _, name := range contextNames {
+		deleteContext(config, name)
+	}
+	for _, name := range clusterNames {
+		delete(config.Clusters, name)
+	}
+	for _, name := range authInfoNames {
+		delete(config.AuthInfos, name)
+	}
+
+	// Save the updated config
+	return m.Save()
+}
+
+// GetCurrentNamespace returns the namespace set for the current context
+func (m *Manager) GetCurrentNamespace() (string, error) {
+	config, err := m.Config()
+	if err != nil {
+		return "", err
+	}
+
+	currentContext := config.CurrentContext
+	if currentContext == "" {
+		return "", ErrNoCurrentContext
+	}
+
+	return m.GetNamespaceForContext(currentContext)
+}
+
+// GetNamespaceForContext returns the namespace for the specified context
+func (m *Manager) GetNamespaceForContext(contextName string) (string, error) {
+	config, err := m.Config()
+	if err != nil {
+		return "", err
+	}
+
+	context, exists := config.Contexts[contextName]
+	if !exists {
+		return "", notFound(ErrContextNotFound, contextName)
+	}
+
+	// If namespace is empty, return "default"
+	if context.Namespace == "" {
+		return "default", nil
+	}
+
+	return context.Namespace, nil
+}
+
+// SetNamespace sets the namespace for the current context
+func (m *Manager) SetNamespace(namespace string) error {
+	return m.SetNamespaceForContext("", namespace)
+}
+
+// SetNamespaceForContext sets the namespace for the specified context
+// If contextName is empty, it uses the current context
+func (m *Manager) SetNamespaceForContext(contextName string, namespace string) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	contextName, err = resolveContextName(config, contextName)
+	if err != nil {
+		return err
+	}
+
+	// Set the namespace
+	config.Contexts[contextName].Namespace = namespace
+
+	// Save the updated config
+	return m.Save()
+}
+
+// GetNamespaces returns all available namespaces for the current context
+//
+// This function attempts to connect to the cluster and list namespaces.
+// If the connection fails, it returns a set of default namespaces.
+func (m *Manager) GetNamespaces() ([]string, error) {
+	return m.GetNamespacesForContext("")
+}
+
+// GetNamespacesForContext returns all available namespaces for the specified context
+// If contextName is empty, it uses the current context
+func (m *Manager) GetNamespacesForContext(contextName string) ([]string, error) {
+	config, err := m.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	contextName, err = resolveContextName(config, contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := m.listNamespaces(config, contextName)
+	if err != nil {
+		// If we can't connect to the cluster, return some default namespaces
+		// This handles the case where the user might be offline or the cluster is unavailable
+		return m.fallback, nil
+	}
+
+	return namespaces, nil
+}
+
+// CreateNamespaceForContext creates a namespace with labels in the specified context's cluster
+//
+// It uses the manager's cluster client, which must implement NamespaceCreator
+// (the default client-go client does). RBAC denials are returned wrapped in
+// ErrForbidden and connection failures in ErrClusterUnreachable. Creating a
+// namespace that already exists is not an error.
+// If contextName is empty, it uses the current context.
+func (m *Manager) CreateNamespaceForContext(contextName, namespace string, labels map[string]string) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	contextName, err = resolveContextName(config, contextName)
+	if err != nil {
+		return err
+	}
+
+	creator, ok := m.client.(NamespaceCreator)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrCreateNotSupported, m.client)
+	}
+
+	start := time.Now()
+	if err := creator.CreateNamespace(config, contextName, namespace, labels); err != nil {
+		m.debugf("creating namespace '%s' in context '%s' failed after %s: %v", namespace, contextName, time.Since(start).Round(time.Millisecond), err)
+		return err
+	}
+	m.debugf("created namespace '%s' in context '%s' in %s", namespace, contextName, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// ListNamespacesForContext returns the namespaces of the specified context's cluster
+//
+// Unlike GetNamespacesForContext it does not fall back to default namespaces:
+// connection failures are returned wrapped in ErrClusterUnreachable and API
+// errors (for example RBAC denials) are returned as-is.
+// If contextName is empty, it uses the current context.
+func (m *Manager) ListNamespacesForContext(contextName string) ([]string, error) {
+	config, err := m.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	contextName, err = resolveContextName(config, contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.listNamespaces(config, contextName)
+}
+
+// listNamespaces lists the namespaces of a context with the cluster client
+func (m *Manager) listNamespaces(config *api.Config, contextName string) ([]string, error) {
+	start := time.Now()
+	namespaces, err := m.client.ListNamespaces(config, contextName)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		m.debugf("listing namespaces of context '%s' failed after %s: %v", contextName, elapsed, err)
+		return nil, err
+	}
+	m.debugf("listed %d namespaces of context '%s' in %s", len(namespaces), contextName, elapsed)
+	return namespaces, nil
+}
+
+// ProbeContext checks whether the cluster of a context answers within the timeout
+//
+// It returns nil if the API server responded (even with an authorization
+// error) and an error wrapping ErrClusterUnreachable otherwise.
+// If contextName is empty, it uses the current context.
+func (m *Manager) ProbeContext(contextName string, timeout time.Duration) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	contextName, err = resolveContextName(config, contextName)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = m.client.Probe(config, contextName, timeout)
+	m.debugf("probed context '%s' in %s", contextName, time.Since(start).Round(time.Millisecond))
+	return err
+}
+
+// DescribeContext returns the details of a context
+// See the package-level DescribeContext for how secrets are handled.
+func (m *Manager) DescribeContext(contextName string, showSecrets bool) (*ContextDetails, error) {
+	config, err := m.Config()
+	if err != nil {
+		return nil, err
+	}
+	return DescribeContext(config, contextName, showSecrets)
+}
+
+// Lint validates the kubeconfig
+func (m *Manager) Lint() ([]Issue, error) {
+	config, err := m.Config()
+	if err != nil {
+		return nil, err
+	}
+	return Lint(config), nil
+}
+
+// Fix applies all safe repairs to the kubeconfig and saves it
+// It returns the issues that were fixed.
+func (m *Manager) Fix() ([]Issue, error) {
+	config, err := m.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	fixed := FixIssues(config, Lint(config))
+	if len(fixed) == 0 {
+		return nil, nil
+	}
+
+	if err := m.Save(); err != nil {
+		return nil, err
+	}
+	return fixed, nil
+}
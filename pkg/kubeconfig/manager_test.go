@@ -0,0 +1,270 @@
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user-cube/kontext/pkg/static"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// fakeClusterClient is a ClusterClient with canned namespaces per context
+// Contexts without namespaces are reported as unreachable.
+type fakeClusterClient struct {
+	namespaces map[string][]string
+}
+
+func (f fakeClusterClient) ListNamespaces(config *api.Config, contextName string) ([]string, error) {
+	namespaces, exists := f.namespaces[contextName]
+	if !exists {
+		return nil, fmt.Errorf("%w: connection refused", ErrClusterUnreachable)
+	}
+	return namespaces, nil
+}
+
+func (f fakeClusterClient) Probe(config *api.Config, contextName string, timeout time.Duration) error {
+	_, err := f.ListNamespaces(config, contextName)
+	return err
+}
+
+func (f fakeClusterClient) CreateNamespace(config *api.Config, contextName, namespace string, labels map[string]string) error {
+	if _, exists := f.namespaces[contextName]; !exists {
+		return fmt.Errorf("%w: namespaces is forbidden", ErrForbidden)
+	}
+	f.namespaces[contextName] = append(f.namespaces[contextName], namespace)
+	return nil
+}
+
+// fixedClock is a Clock that always returns the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// testConfig returns an in-memory kubeconfig with two contexts
+func testConfig() *api.Config {
+	config := api.NewConfig()
+	config.Clusters["dev"] = &api.Cluster{Server: "https://dev.example.com"}
+	config.Clusters["prod"] = &api.Cluster{Server: "https://prod.example.com"}
+	config.AuthInfos["dev"] = &api.AuthInfo{Token: "dev-token"}
+	config.AuthInfos["prod"] = &api.AuthInfo{Token: "prod-token"}
+	config.Contexts["dev"] = &api.Context{Cluster: "dev", AuthInfo: "dev", Namespace: "team"}
+	config.Contexts["prod"] = &api.Context{Cluster: "prod", AuthInfo: "prod"}
+	config.CurrentContext = "dev"
+	return config
+}
+
+func TestManagerInMemory(t *testing.T) {
+	config := testConfig()
+
+	var writes []string
+	writer := WriterFunc(func(config *api.Config) error {
+		writes = append(writes, config.CurrentContext)
+		return nil
+	})
+	m := NewManager(WithConfig(config), WithWriter(writer))
+
+	if err := m.SwitchContext("prod"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+	if err := m.SetNamespace("payments"); err != nil {
+		t.Fatalf("SetNamespace() error = %v", err)
+	}
+	if err := m.SwitchContext("missing"); !errors.Is(err, ErrContextNotFound) {
+		t.Errorf("SwitchContext(missing) error = %v, want %v", err, ErrContextNotFound)
+	}
+
+	if !reflect.DeepEqual(writes, []string{"prod", "prod"}) {
+		t.Errorf("writes = %v, want one write per successful change", writes)
+	}
+
+	namespace, err := m.GetCurrentNamespace()
+	if err != nil {
+		t.Fatalf("GetCurrentNamespace() error = %v", err)
+	}
+	if namespace != "payments" {
+		t.Errorf("GetCurrentNamespace() = %v, want payments", namespace)
+	}
+	if config.Contexts["prod"].Namespace != "payments" {
+		t.Errorf("in-memory config was not updated")
+	}
+
+	if err := m.DeleteContext("dev"); err != nil {
+		t.Fatalf("DeleteContext() error = %v", err)
+	}
+	if _, exists := config.Clusters["dev"]; exists {
+		t.Errorf("DeleteContext() kept the orphan cluster")
+	}
+}
+
+func TestManagerWithoutWriter(t *testing.T) {
+	m := NewManager(WithConfig(testConfig()))
+
+	if err := m.SwitchContext("prod"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+	current, err := m.GetCurrentContext()
+	if err != nil {
+		t.Fatalf("GetCurrentContext() error = %v", err)
+	}
+	if current != "prod" {
+		t.Errorf("GetCurrentContext() = %v, want prod", current)
+	}
+}
+
+func TestManagerClusterClient(t *testing.T) {
+	client := fakeClusterClient{namespaces: map[string][]string{"dev": {"default", "team"}}}
+	m := NewManager(WithConfig(testConfig()), WithClusterClient(client))
+
+	namespaces, err := m.ListNamespacesForContext("")
+	if err != nil {
+		t.Fatalf("ListNamespacesForContext() error = %v", err)
+	}
+	if !reflect.DeepEqual(namespaces, []string{"default", "team"}) {
+		t.Errorf("ListNamespacesForContext() = %v", namespaces)
+	}
+
+	if _, err := m.ListNamespacesForContext("prod"); !errors.Is(err, ErrClusterUnreachable) {
+		t.Errorf("ListNamespacesForContext(prod) error = %v, want %v", err, ErrClusterUnreachable)
+	}
+
+	namespaces, err = m.GetNamespacesForContext("prod")
+	if err != nil {
+		t.Fatalf("GetNamespacesForContext(prod) error = %v", err)
+	}
+	if !reflect.DeepEqual(namespaces, static.FallBackNamespace) {
+		t.Errorf("GetNamespacesForContext(prod) = %v, want the default fallback namespaces", namespaces)
+	}
+
+	fallback := []string{"default", "apps"}
+	namespaces, err = NewManager(WithConfig(testConfig()), WithClusterClient(client), WithFallbackNamespaces(fallback)).GetNamespacesForContext("prod")
+	if err != nil {
+		t.Fatalf("GetNamespacesForContext(prod) error = %v", err)
+	}
+	if !reflect.DeepEqual(namespaces, fallback) {
+		t.Errorf("GetNamespacesForContext(prod) = %v, want the configured fallback %v", namespaces, fallback)
+	}
+
+	if _, err := m.GetNamespacesForContext("missing"); !errors.Is(err, ErrContextNotFound) {
+		t.Errorf("GetNamespacesForContext(missing) error = %v, want %v", err, ErrContextNotFound)
+	}
+
+	if err := m.ProbeContext("dev", time.Second); err != nil {
+		t.Errorf("ProbeContext(dev) error = %v", err)
+	}
+	if err := m.ProbeContext("prod", time.Second); !errors.Is(err, ErrClusterUnreachable) {
+		t.Errorf("ProbeContext(prod) error = %v, want %v", err, ErrClusterUnreachable)
+	}
+}
+
+func TestManagerCreateNamespace(t *testing.T) {
+	client := fakeClusterClient{namespaces: map[string][]string{"dev": {"default"}}}
+	m := NewManager(WithConfig(testConfig()), WithClusterClient(client))
+
+	if err := m.CreateNamespaceForContext("", "team", map[string]string{"owner": "me"}); err != nil {
+		t.Fatalf("CreateNamespaceForContext() error = %v", err)
+	}
+	namespaces, err := m.ListNamespacesForContext("dev")
+	if err != nil {
+		t.Fatalf("ListNamespacesForContext() error = %v", err)
+	}
+	if !reflect.DeepEqual(namespaces, []string{"default", "team"}) {
+		t.Errorf("ListNamespacesForContext() = %v, want the created namespace", namespaces)
+	}
+
+	if err := m.CreateNamespaceForContext("prod", "team", nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("CreateNamespaceForContext(prod) error = %v, want %v", err, ErrForbidden)
+	}
+	if err := m.CreateNamespaceForContext("missing", "team", nil); !errors.Is(err, ErrContextNotFound) {
+		t.Errorf("CreateNamespaceForContext(missing) error = %v, want %v", err, ErrContextNotFound)
+	}
+}
+
+func TestManagerWithPathLoadsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(*testConfig(), path); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	m := NewManager(WithPath(path))
+	if err := m.SwitchContext("prod"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+
+	// Changes made behind the manager's back are not seen after the first load
+	other := testConfig()
+	other.Contexts["staging"] = &api.Context{Cluster: "dev", AuthInfo: "dev"}
+	other.CurrentContext = "staging"
+	if err := clientcmd.WriteToFile(*other, path); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	if err := m.SwitchContext("staging"); !errors.Is(err, ErrContextNotFound) {
+		t.Errorf("SwitchContext(staging) error = %v, want %v", err, ErrContextNotFound)
+	}
+
+	// A new manager sees the file as written
+	current, err := NewManager(WithPath(path)).GetCurrentContext()
+	if err != nil {
+		t.Fatalf("GetCurrentContext() error = %v", err)
+	}
+	if current != "staging" {
+		t.Errorf("GetCurrentContext() = %v, want staging", current)
+	}
+
+	if _, err := NewManager(WithPath(filepath.Join(t.TempDir(), "missing"))).Config(); !errors.Is(err, ErrKubeConfigLoad) {
+		t.Errorf("Config() error = %v, want %v", err, ErrKubeConfigLoad)
+	}
+}
+
+func TestManagerClock(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := NewManager(WithConfig(testConfig()), WithClock(fixedClock(now)))
+	if got := m.Now(); !got.Equal(now) {
+		t.Errorf("Now() = %v, want %v", got, now)
+	}
+}
+
+func TestManagerLogf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(*testConfig(), path); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	var messages []string
+	logf := func(format string, args ...interface{}) {
+		messages = append(messages, fmt.Sprintf(format, args...))
+	}
+	client := fakeClusterClient{namespaces: map[string][]string{"dev": {"default", "team"}}}
+	m := NewManager(WithPath(path), WithClusterClient(client), WithLogf(logf))
+
+	if _, err := m.ListNamespacesForContext("dev"); err != nil {
+		t.Fatalf("ListNamespacesForContext() error = %v", err)
+	}
+	if err := m.SwitchContext("prod"); err != nil {
+		t.Fatalf("SwitchContext() error = %v", err)
+	}
+
+	want := []string{
+		"loaded kubeconfig in",
+		"listed 2 namespaces of context 'dev' in",
+		"saved kubeconfig in",
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("Logf messages = %q, want %d messages", messages, len(want))
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(messages[i], prefix) {
+			t.Errorf("message %d = %q, want prefix %q", i, messages[i], prefix)
+		}
+	}
+	if !strings.HasSuffix(messages[0], path) {
+		t.Errorf("load message = %q, want the loaded file %s", messages[0], path)
+	}
+}
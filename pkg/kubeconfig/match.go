@@ -0,0 +1,112 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MatchContexts returns the sorted context names matching any of the patterns
+//
+// Patterns are shell globs (pr-*, dev-?) unless useRegex is true, in which case
+// they are regular expressions matched against the whole name. Unlike file
+// globs, * also matches "/" so patterns work with EKS ARNs. A pattern
+// without glob characters must name an existing context exactly, and every
+// pattern must match at least one context.
+func MatchContexts(contextNames []string, patterns []string, useRegex bool) ([]string, error) {
+	matched := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		re, err := compilePattern(pattern, useRegex)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, name := range contextNames {
+			if re.MatchString(name) {
+				matched[name] = true
+				found = true
+			}
+		}
+
+		if !found {
+			if !useRegex && !IsPattern(pattern) {
+				return nil, notFound(ErrContextNotFound, pattern)
+			}
+			return nil, fmt.Errorf("%w: '%s'", ErrNoMatchingContexts, pattern)
+		}
+	}
+
+	result := make([]string, 0, len(matched))
+	for name := range matched {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// MatchPattern reports whether a context name matches a shell glob
+// A pattern without glob characters must equal the name.
+func MatchPattern(name, pattern string) (bool, error) {
+	re, err := compilePattern(pattern, false)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(name), nil
+}
+
+// compilePattern compiles a glob or regular expression matching whole context names
+func compilePattern(pattern string, useRegex bool) (*regexp.Regexp, error) {
+	expr := pattern
+	if !useRegex {
+		expr = globToRegexp(pattern)
+	}
+
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		if useRegex {
+			return nil, fmt.Errorf("invalid regular expression '%s': %w", pattern, err)
+		}
+		return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	return re, nil
+}
+
+// IsPattern reports whether s contains shell glob characters
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// globToRegexp converts a shell glob into an equivalent regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	inClass := false
+	classStart := false
+	for _, r := range glob {
+		switch {
+		case inClass && classStart && r == '!':
+			// Shell negation [!a] is written [^a] in regular expressions
+			classStart = false
+			b.WriteRune('^')
+		case inClass:
+			classStart = false
+			if r == ']' {
+				inClass = false
+			}
+			b.WriteRune(r)
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		case r == '[':
+			inClass = true
+			classStart = true
+			b.WriteRune(r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package kubeconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchContexts(t *testing.T) {
+	contexts := []string{
+		"dev",
+		"pr-101",
+		"pr-102",
+		"prod",
+		"arn:aws:eks:eu-west-1:123456789012:cluster/preview-7",
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		useRegex bool
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "Exact name",
+			patterns: []string{"dev"},
+			want:     []string{"dev"},
+		},
+		{
+			name:     "Glob pattern",
+			patterns: []string{"pr-*"},
+			want:     []string{"pr-101", "pr-102"},
+		},
+		{
+			name:     "Glob star matches slashes",
+			patterns: []string{"arn:*/preview-*"},
+			want:     []string{"arn:aws:eks:eu-west-1:123456789012:cluster/preview-7"},
+		},
+		{
+			name:     "Negated character class",
+			patterns: []string{"pr-10[!1]"},
+			want:     []string{"pr-102"},
+		},
+		{
+			name:     "Several patterns are merged",
+			patterns: []string{"dev", "pr-10?"},
+			want:     []string{"dev", "pr-101", "pr-102"},
+		},
+		{
+			name:     "Regex must match the whole name",
+			patterns: []string{"pr"},
+			useRegex: true,
+			wantErr:  true,
+		},
+		{
+			name:     "Regex",
+			patterns: []string{"pr-\\d+|prod"},
+			useRegex: true,
+			want:     []string{"pr-101", "pr-102", "prod"},
+		},
+		{
+			name:     "Unknown exact name",
+			patterns: []string{"staging"},
+			wantErr:  true,
+		},
+		{
+			name:     "Pattern matching nothing",
+			patterns: []string{"qa-*"},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid regex",
+			patterns: []string{"("},
+			useRegex: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchContexts(contexts, tt.patterns, tt.useRegex)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MatchContexts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchContexts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package output provides structured output formats for kontext commands
+//
+// This package defines the documents printed by kontext commands as Go types
+// with stable JSON/YAML schemas, and renders them in the formats selected
+// with -o:
+// - json and yaml for scripts
+// - name for one name per line
+// - wide for a table with cluster, server, user, namespace and auth columns
+// - go-template=... for custom output
+//
+// The default text format is rendered by the ui package.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Supported format kinds
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
+	FormatName       = "name"
+	FormatWide       = "wide"
+	FormatGoTemplate = "go-template"
+)
+
+// Object is implemented by every document that can be printed
+type Object interface {
+	// Names returns the names printed by -o name
+	Names() []string
+	// Table returns the header and rows printed by -o wide
+	Table() ([]string, [][]string)
+}
+
+// Format is a parsed -o value
+type Format struct {
+	Kind     string
+	Template string
+}
+
+// ParseFormat parses a -o value
+// An empty value selects the default text format.
+func ParseFormat(value string) (Format, error) {
+	if value == "" {
+		return Format{Kind: FormatText}, nil
+	}
+
+	if tmpl, ok := strings.CutPrefix(value, FormatGoTemplate+"="); ok {
+		if tmpl == "" {
+			return Format{}, fmt.Errorf("go-template format requires a template, e.g. -o go-template='{{.name}}'")
+		}
+		if _, err := template.New("output").Parse(tmpl); err != nil {
+			return Format{}, fmt.Errorf("invalid go-template: %w", err)
+		}
+		return Format{Kind: FormatGoTemplate, Template: tmpl}, nil
+	}
+
+	switch value {
+	case FormatText, FormatJSON, FormatYAML, FormatName, FormatWide:
+		return Format{Kind: value}, nil
+	default:
+		return Format{}, fmt.Errorf("unsupported output format '%s' (use json, yaml, name, wide or go-template=...)", value)
+	}
+}
+
+// IsText reports whether the default human-readable format is selected
+func (f Format) IsText() bool {
+	return f.Kind == FormatText
+}
+
+// Print renders an object in the given structured format
+// The text format is not handled here and returns an error.
+func Print(w io.Writer, format Format, obj Object) error {
+	switch format.Kind {
+	case FormatJSON:
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+
+	case FormatYAML:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("error encoding YAML: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+
+	case FormatName:
+		for _, name := range obj.Names() {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatWide:
+		header, rows := obj.Table()
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+
+	case FormatGoTemplate:
+		return printTemplate(w, format.Template, obj)
+
+	default:
+		return fmt.Errorf("format '%s' is not a structured format", format.Kind)
+	}
+}
+
+// printTemplate executes a Go template against the JSON representation of obj,
+// so templates use the same field names as -o json (like kubectl)
+func printTemplate(w io.Writer, tmpl string, obj Object) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("error encoding JSON: %w", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("error decoding JSON: %w", err)
+	}
+
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid go-template: %w", err)
+	}
+	if err := t.Execute(w, value); err != nil {
+		return fmt.Errorf("error executing go-template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+)
+
+// testList returns a small context list used by the tests
+func testList() ContextList {
+	return ContextList{
+		CurrentContext: "prod",
+		Contexts: []ContextInfo{
+			{Name: "dev", Cluster: "dev-cluster", Server: "https://dev.example.com", User: "dev-user", Namespace: "default", AuthMethod: "token"},
+			{Name: "prod", Current: true, Cluster: "prod-cluster", Server: "https://prod.example.com", User: "prod-user", Namespace: "payments", AuthMethod: "exec"},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantKind string
+		wantErr  bool
+	}{
+		{value: "", wantKind: FormatText},
+		{value: "text", wantKind: FormatText},
+		{value: "json", wantKind: FormatJSON},
+		{value: "yaml", wantKind: FormatYAML},
+		{value: "name", wantKind: FormatName},
+		{value: "wide", wantKind: FormatWide},
+		{value: "go-template={{.name}}", wantKind: FormatGoTemplate},
+		{value: "go-template=", wantErr: true},
+		{value: "go-template={{.name", wantErr: true},
+		{value: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			format, err := ParseFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && format.Kind != tt.wantKind {
+				t.Errorf("ParseFormat(%q) kind = %v, want %v", tt.value, format.Kind, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		want   []string
+	}{
+		{
+			name:   "JSON uses the documented field names",
+			format: Format{Kind: FormatJSON},
+			want:   []string{`"currentContext": "prod"`, `"authMethod": "exec"`},
+		},
+		{
+			name:   "YAML",
+			format: Format{Kind: FormatYAML},
+			want:   []string{"currentContext: prod", "- authMethod: token"},
+		},
+		{
+			name:   "Name",
+			format: Format{Kind: FormatName},
+			want:   []string{"dev\nprod\n"},
+		},
+		{
+			name:   "Wide",
+			format: Format{Kind: FormatWide},
+			want:   []string{"CURRENT", "SERVER", "https://prod.example.com", "payments"},
+		},
+		{
+			name:   "Go template uses JSON field names",
+			format: Format{Kind: FormatGoTemplate, Template: `{{range .contexts}}{{.name}}={{.namespace}};{{end}}`},
+			want:   []string{"dev=default;prod=payments;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Print(&buf, tt.format, testList()); err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Print() output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	if err := Print(&bytes.Buffer{}, Format{Kind: FormatText}, testList()); err == nil {
+		t.Error("Print() with the text format should fail")
+	}
+}
+
+func TestEmbeddedContextInfoIsInlined(t *testing.T) {
+	var buf bytes.Buffer
+	status := Status{ContextInfo: ContextInfo{Name: "prod"}, Source: "/kubeconfig"}
+	if err := Print(&buf, Format{Kind: FormatJSON}, status); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "ContextInfo") || !strings.Contains(buf.String(), `"name": "prod"`) {
+		t.Errorf("Print() did not inline ContextInfo:\n%s", buf.String())
+	}
+}
+
+func TestLintReportNames(t *testing.T) {
+	var buf bytes.Buffer
+	report := LintReport{Issues: []kubeconfig.Issue{
+		{Severity: kubeconfig.SeverityError, Kind: "context", Name: "bad"},
+		{Severity: kubeconfig.SeverityWarning, Kind: "cluster", Name: "orphan"},
+	}}
+	if err := Print(&buf, Format{Kind: FormatName}, report); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	if buf.String() != "context/bad\ncluster/orphan\n" {
+		t.Errorf("Print() = %q, want kind/name per issue", buf.String())
+	}
+}
@@ -0,0 +1,266 @@
+package output
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/user-cube/kontext/pkg/audit"
+	"github.com/user-cube/kontext/pkg/kubeconfig"
+)
+
+// ContextDetails is the document printed by `kontext show`
+type ContextDetails = kubeconfig.ContextDetails
+
+// ContextInfo is the summary of a single context used by list, current and status
+type ContextInfo struct {
+	Name       string            `json:"name"`
+	Current    bool              `json:"current"`
+	Cluster    string            `json:"cluster"`
+	Server     string            `json:"server"`
+	User       string            `json:"user"`
+	Namespace  string            `json:"namespace"`
+	AuthMethod string            `json:"authMethod"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// ContextList is the document printed by `kontext list`
+type ContextList struct {
+	CurrentContext string        `json:"currentContext"`
+	Contexts       []ContextInfo `json:"contexts"`
+}
+
+// CurrentContext is the document printed by `kontext current`
+type CurrentContext struct {
+	ContextInfo
+}
+
+// NamespaceInfo is the document printed by `kontext ns --show`
+type NamespaceInfo struct {
+	Context   string `json:"context"`
+	Namespace string `json:"namespace"`
+}
+
+// Status is the document printed by `kontext status`
+type Status struct {
+	ContextInfo
+	Source string  `json:"source,omitempty"`
+	Expiry *Expiry `json:"expiry,omitempty"`
+}
+
+// Expiry describes a time-boxed switch to the current context
+type Expiry struct {
+	Expires           time.Time `json:"expires"`
+	Remaining         string    `json:"remaining"`
+	FallbackContext   string    `json:"fallbackContext"`
+	FallbackNamespace string    `json:"fallbackNamespace,omitempty"`
+}
+
+// AuditLog is the document printed by `kontext audit`
+type AuditLog struct {
+	Entries []audit.Entry `json:"entries"`
+}
+
+// Stats is the document printed by `kontext stats`
+type Stats struct {
+	User       string         `json:"user"`
+	Host       string         `json:"host"`
+	Since      time.Time      `json:"since,omitzero"`
+	UnusedDays int            `json:"unusedDays,omitempty"`
+	Contexts   []ContextStats `json:"contexts"`
+}
+
+// ContextStats is the usage of a single context
+type ContextStats struct {
+	UsageStats
+	// Unused marks contexts not switched to in UnusedDays
+	Unused     bool         `json:"unused"`
+	Namespaces []UsageStats `json:"namespaces,omitempty"`
+}
+
+// LintReport is the document printed by `kontext lint`
+type LintReport struct {
+	Issues []kubeconfig.Issue `json:"issues"`
+	Fixed  []kubeconfig.Issue `json:"fixed"`
+	Errors int                `json:"errors"`
+}
+
+// UsageStats is the usage of a context or namespace
+type UsageStats struct {
+	Name             string    `json:"name"`
+	Switches         int       `json:"switches"`
+	LastUsed         time.Time `json:"lastUsed,omitzero"`
+	TimeSpentSeconds int64     `json:"timeSpentSeconds"`
+}
+
+// NewContextInfo summarizes context details
+func NewContextInfo(details *kubeconfig.ContextDetails) ContextInfo {
+	return ContextInfo{
+		Name:       details.Name,
+		Current:    details.Current,
+		Cluster:    details.Cluster.Name,
+		Server:     details.Cluster.Server,
+		User:       details.User.Name,
+		Namespace:  details.Namespace,
+		AuthMethod: details.User.AuthMethod,
+	}
+}
+
+// contextHeader is the wide table header for context rows
+var contextHeader = []string{"CURRENT", "NAME", "CLUSTER", "SERVER", "USER", "NAMESPACE", "AUTH"}
+
+// row returns the wide table row of a context
+func (c ContextInfo) row() []string {
+	current := ""
+	if c.Current {
+		current = "*"
+	}
+	return []string{current, c.Name, c.Cluster, c.Server, c.User, c.Namespace, c.AuthMethod}
+}
+
+// Names implements Object
+func (l ContextList) Names() []string {
+	names := make([]string, len(l.Contexts))
+	for i, c := range l.Contexts {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Table implements Object
+func (l ContextList) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l.Contexts))
+	for i, c := range l.Contexts {
+		rows[i] = c.row()
+	}
+	return contextHeader, rows
+}
+
+// Names implements Object
+func (c CurrentContext) Names() []string {
+	return []string{c.Name}
+}
+
+// Table implements Object
+func (c CurrentContext) Table() ([]string, [][]string) {
+	return contextHeader, [][]string{c.row()}
+}
+
+// Names implements Object
+func (n NamespaceInfo) Names() []string {
+	return []string{n.Namespace}
+}
+
+// Table implements Object
+func (n NamespaceInfo) Table() ([]string, [][]string) {
+	return []string{"CONTEXT", "NAMESPACE"}, [][]string{{n.Context, n.Namespace}}
+}
+
+// Names implements Object
+func (s Status) Names() []string {
+	return []string{s.Name}
+}
+
+// Table implements Object
+func (s Status) Table() ([]string, [][]string) {
+	header := append(append([]string{}, contextHeader...), "SOURCE")
+	return header, [][]string{append(s.row(), s.Source)}
+}
+
+// Details wraps ContextDetails with kontext's own metadata so it can be printed as an Object
+type Details struct {
+	*ContextDetails
+	Aliases []string          `json:"aliases,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// Names implements Object
+func (d Details) Names() []string {
+	return []string{d.Name}
+}
+
+// Table implements Object
+func (d Details) Table() ([]string, [][]string) {
+	return contextHeader, [][]string{NewContextInfo(d.ContextDetails).row()}
+}
+
+// Names implements Object
+// Each entry is named by the context it changed to, or the first context it removed.
+func (l AuditLog) Names() []string {
+	names := make([]string, 0, len(l.Entries))
+	for _, entry := range l.Entries {
+		name := entry.NewContext
+		if name == "" && len(entry.Contexts) > 0 {
+			name = entry.Contexts[0]
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// Table implements Object
+func (l AuditLog) Table() ([]string, [][]string) {
+	rows := make([][]string, len(l.Entries))
+	for i, entry := range l.Entries {
+		rows[i] = []string{
+			entry.Time.Local().Format(time.DateTime),
+			entry.Operation,
+			entry.User,
+			entry.Host,
+			entry.TTY,
+			entry.Kubeconfig,
+			entry.Summary(),
+		}
+	}
+	return []string{"TIME", "OPERATION", "USER", "HOST", "TTY", "KUBECONFIG", "CHANGE"}, rows
+}
+
+// Names implements Object
+// Each issue is named by its kind and entry, like kubectl's kind/name.
+func (r LintReport) Names() []string {
+	names := make([]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		names[i] = issue.Kind + "/" + issue.Name
+	}
+	return names
+}
+
+// Table implements Object
+func (r LintReport) Table() ([]string, [][]string) {
+	rows := make([][]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		rows[i] = []string{string(issue.Severity), issue.Code, issue.Kind, issue.Name, issue.Message}
+	}
+	return []string{"SEVERITY", "CODE", "KIND", "NAME", "MESSAGE"}, rows
+}
+
+// Names implements Object
+func (s Stats) Names() []string {
+	names := make([]string, len(s.Contexts))
+	for i, c := range s.Contexts {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Table implements Object
+func (s Stats) Table() ([]string, [][]string) {
+	rows := make([][]string, len(s.Contexts))
+	for i, c := range s.Contexts {
+		lastUsed := ""
+		if !c.LastUsed.IsZero() {
+			lastUsed = c.LastUsed.Local().Format(time.DateTime)
+		}
+		unused := ""
+		if c.Unused {
+			unused = "*"
+		}
+		rows[i] = []string{
+			c.Name,
+			strconv.Itoa(c.Switches),
+			lastUsed,
+			(time.Duration(c.TimeSpentSeconds) * time.Second).String(),
+			unused,
+		}
+	}
+	return []string{"CONTEXT", "SWITCHES", "LAST USED", "TIME SPENT", "UNUSED"}, rows
+}
@@ -0,0 +1,260 @@
+// Package pin pins directories to a context with .kontext files
+//
+// A .kontext file declares the context and namespace a project works
+// against. The shell integration looks for the nearest file above the working
+// directory and points KUBECONFIG at a session kubeconfig holding only that
+// context, so the global kubeconfig is never changed. Files must be allowed
+// before they take effect, and allowing a file trusts its current content only.
+package pin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user-cube/kontext/pkg/history"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+)
+
+// FileName is the name of pin files
+const FileName = ".kontext"
+
+var (
+	// ErrInvalidPin is returned when a .kontext file can't be parsed
+	ErrInvalidPin = errors.New("invalid .kontext file")
+	// ErrNotAllowed is returned for .kontext files that are new or changed since they were allowed
+	ErrNotAllowed = errors.New(".kontext file is not allowed")
+	// ErrFragmentMode is returned when pinning while kubeconfig fragments are in use
+	// kontext reads the fragments rather than KUBECONFIG, so it would not follow the pin.
+	ErrFragmentMode = errors.New(".kontext files can't be used with kubeconfig fragments")
+)
+
+// Pin is the content of a .kontext file
+type Pin struct {
+	// Context is the context name or alias to use
+	Context string `json:"context"`
+	// Namespace optionally overrides the context's namespace
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Find returns the nearest .kontext file in dir or its parents, or "" if there is none
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates a .kontext file
+func Load(path string) (*Pin, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := yaml.YAMLToJSONStrict(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrInvalidPin, path, err)
+	}
+	var pin Pin
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&pin); err != nil {
+		return nil, fmt.Errorf("%w %s: %s", ErrInvalidPin, path, strings.TrimPrefix(err.Error(), "json: "))
+	}
+	if pin.Context == "" {
+		return nil, fmt.Errorf("%w %s: context is required", ErrInvalidPin, path)
+	}
+	return &pin, nil
+}
+
+// allowedPath returns the path of the file recording allowed .kontext files
+func allowedPath() string {
+	return filepath.Join(history.GetStateDir(), "allowed.json")
+}
+
+// loadAllowed returns the allowed .kontext files mapped to the hash of their content
+func loadAllowed() (map[string]string, error) {
+	allowed := make(map[string]string)
+	data, err := os.ReadFile(allowedPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return allowed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading allowed .kontext files: %w", err)
+	}
+	if err := json.Unmarshal(data, &allowed); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", allowedPath(), err)
+	}
+	return allowed, nil
+}
+
+// saveAllowed writes the allowed .kontext files
+func saveAllowed(allowed map[string]string) error {
+	data, err := json.MarshalIndent(allowed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding allowed .kontext files: %w", err)
+	}
+	if err := os.MkdirAll(history.GetStateDir(), 0o700); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+	if err := os.WriteFile(allowedPath(), data, 0o600); err != nil {
+		return fmt.Errorf("error writing allowed .kontext files: %w", err)
+	}
+	return nil
+}
+
+// hashFile returns the hash of a file's content
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CheckAllowed returns nil if the file was allowed with its current content, or ErrNotAllowed
+func CheckAllowed(path string) error {
+	allowed, err := loadAllowed()
+	if err != nil {
+		return err
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+	switch allowed[path] {
+	case hash:
+		return nil
+	case "":
+		return fmt.Errorf("%w: %s", ErrNotAllowed, path)
+	default:
+		return fmt.Errorf("%w: %s changed since it was allowed", ErrNotAllowed, path)
+	}
+}
+
+// Allow trusts the current content of a .kontext file
+func Allow(path string) error {
+	if _, err := Load(path); err != nil {
+		return err
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+	allowed, err := loadAllowed()
+	if err != nil {
+		return err
+	}
+	allowed[path] = hash
+	return saveAllowed(allowed)
+}
+
+// Deny stops trusting a .kontext file
+// Denying a file that isn't allowed is not an error.
+func Deny(path string) error {
+	allowed, err := loadAllowed()
+	if err != nil {
+		return err
+	}
+	delete(allowed, path)
+	return saveAllowed(allowed)
+}
+
+// SessionPath returns the session kubeconfig used for a .kontext file in a shell
+// session identifies the shell, so shells in the same directory don't share
+// a session; an empty session is keyed by the file alone.
+func SessionPath(path, session string) string {
+	sum := sha256.Sum256([]byte(path + "\x00" + session))
+	return filepath.Join(history.GetStateDir(), "pins", hex.EncodeToString(sum[:8])+".yaml")
+}
+
+// WriteSession writes a kubeconfig with only the pinned context, its cluster and user
+// contextName is the pin's context with aliases already resolved. The session
+// lives in the state directory, so it never carries the credentials of other
+// contexts and is only readable by the user.
+func WriteSession(config *api.Config, contextName, namespace, path string) error {
+	context, exists := config.Contexts[contextName]
+	if !exists {
+		return fmt.Errorf("context '%s' does not exist", contextName)
+	}
+
+	pinned := context.DeepCopy()
+	if namespace != "" {
+		pinned.Namespace = namespace
+	}
+	session := api.NewConfig()
+	session.CurrentContext = contextName
+	session.Contexts[contextName] = pinned
+	if cluster, exists := config.Clusters[context.Cluster]; exists {
+		session.Clusters[context.Cluster] = cluster.DeepCopy()
+	}
+	if user, exists := config.AuthInfos[context.AuthInfo]; exists {
+		session.AuthInfos[context.AuthInfo] = user.DeepCopy()
+	}
+
+	data, err := clientcmd.Write(*session)
+	if err != nil {
+		return fmt.Errorf("error encoding session kubeconfig: %w", err)
+	}
+
+	// MkdirAll keeps the mode of an existing directory
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("error creating session directory: %w", err)
+	}
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return fmt.Errorf("error securing session directory: %w", err)
+	}
+
+	// Write through a temporary file, created with mode 0600, so the session
+	// is never readable by others or seen half written
+	file, err := os.CreateTemp(dir, ".session-*")
+	if err != nil {
+		return fmt.Errorf("error writing session kubeconfig: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error writing session kubeconfig: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing session kubeconfig: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error writing session kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// RemoveSession removes the session kubeconfig of a .kontext file in a shell
+// Removing a session that doesn't exist is not an error.
+func RemoveSession(path, session string) error {
+	err := os.Remove(SessionPath(path, session))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package pin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/user-cube/kontext/pkg/history"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// useTempDirs returns a project directory and points the state directory at a temporary one
+func useTempDirs(t *testing.T) (string, func()) {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "kontext-pin-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	originalEnv := os.Getenv(history.StateDirEnv)
+	_ = os.Setenv(history.StateDirEnv, filepath.Join(tmpDir, "state"))
+
+	project := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(project, "src", "pkg"), 0o755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+
+	return project, func() {
+		_ = os.Setenv(history.StateDirEnv, originalEnv)
+		_ = os.RemoveAll(tmpDir)
+	}
+}
+
+func TestFindAndLoad(t *testing.T) {
+	project, cleanup := useTempDirs(t)
+	defer cleanup()
+
+	if path, err := Find(filepath.Join(project, "src")); err != nil || path != "" {
+		t.Fatalf("Find() without a file = %q, %v", path, err)
+	}
+
+	path := filepath.Join(project, FileName)
+	if err := os.WriteFile(path, []byte("context: prod\nnamespace: payments\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write pin: %v", err)
+	}
+
+	found, err := Find(filepath.Join(project, "src", "pkg"))
+	if err != nil || found != path {
+		t.Fatalf("Find() = %q, %v, want %q", found, err, path)
+	}
+
+	pin, err := Load(found)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if pin.Context != "prod" || pin.Namespace != "payments" {
+		t.Errorf("Load() = %+v", pin)
+	}
+
+	for _, content := range []string{"namespace: payments\n", "context: prod\ncluster: x\n"} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write pin: %v", err)
+		}
+		if _, err := Load(path); !errors.Is(err, ErrInvalidPin) {
+			t.Errorf("Load(%q) error = %v, want %v", content, err, ErrInvalidPin)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	project, cleanup := useTempDirs(t)
+	defer cleanup()
+
+	path := filepath.Join(project, FileName)
+	if err := os.WriteFile(path, []byte("context: prod\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write pin: %v", err)
+	}
+
+	if err := CheckAllowed(path); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("CheckAllowed() of a new file error = %v, want %v", err, ErrNotAllowed)
+	}
+	if err := Allow(path); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if err := CheckAllowed(path); err != nil {
+		t.Errorf("CheckAllowed() after Allow() error = %v", err)
+	}
+
+	// Changing the file revokes the trust
+	if err := os.WriteFile(path, []byte("context: other\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write pin: %v", err)
+	}
+	if err := CheckAllowed(path); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("CheckAllowed() of a changed file error = %v, want %v", err, ErrNotAllowed)
+	}
+
+	if err := Allow(path); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if err := Deny(path); err != nil {
+		t.Fatalf("Deny() error = %v", err)
+	}
+	if err := CheckAllowed(path); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("CheckAllowed() after Deny() error = %v, want %v", err, ErrNotAllowed)
+	}
+}
+
+func TestWriteSession(t *testing.T) {
+	project, cleanup := useTempDirs(t)
+	defer cleanup()
+
+	config := api.NewConfig()
+	config.CurrentContext = "dev"
+	config.Contexts["dev"] = &api.Context{Cluster: "c1", AuthInfo: "u1", Namespace: "default"}
+	config.Contexts["prod"] = &api.Context{Cluster: "c2", AuthInfo: "u2", Namespace: "default"}
+	config.Clusters["c1"] = &api.Cluster{Server: "https://dev.example.com"}
+	config.Clusters["c2"] = &api.Cluster{Server: "https://prod.example.com"}
+	config.AuthInfos["u1"] = &api.AuthInfo{Token: "dev-token"}
+	config.AuthInfos["u2"] = &api.AuthInfo{Token: "prod-token"}
+
+	pinPath := filepath.Join(project, FileName)
+	path := SessionPath(pinPath, "1234")
+	if other := SessionPath(pinPath, "5678"); other == path {
+		t.Errorf("SessionPath() = %s for two shells", path)
+	}
+	if err := WriteSession(config, "prod", "payments", path); err != nil {
+		t.Fatalf("WriteSession() error = %v", err)
+	}
+
+	session, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if session.CurrentContext != "prod" || session.Contexts["prod"].Namespace != "payments" {
+		t.Errorf("session = %s/%s", session.CurrentContext, session.Contexts["prod"].Namespace)
+	}
+	if session.Clusters["c2"].Server != "https://prod.example.com" || session.AuthInfos["u2"].Token != "prod-token" {
+		t.Errorf("session lost the pinned cluster or user: %v %v", session.Clusters, session.AuthInfos)
+	}
+	if len(session.Contexts) != 1 || len(session.Clusters) != 1 || len(session.AuthInfos) != 1 {
+		t.Errorf("session kept other entries: %v %v %v", session.Contexts, session.Clusters, session.AuthInfos)
+	}
+	for name, want := range map[string]os.FileMode{path: 0o600, filepath.Dir(path): 0o700} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("mode of %s = %v, want %v", name, info.Mode().Perm(), want)
+		}
+	}
+	if config.CurrentContext != "dev" || config.Contexts["prod"].Namespace != "default" {
+		t.Error("WriteSession() modified the source config")
+	}
+
+	if err := WriteSession(config, "missing", "", path); err == nil {
+		t.Error("WriteSession() with an unknown context succeeded")
+	}
+
+	if err := RemoveSession(pinPath, "1234"); err != nil {
+		t.Fatalf("RemoveSession() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("session still exists after RemoveSession(): %v", err)
+	}
+	if err := RemoveSession(pinPath, "1234"); err != nil {
+		t.Errorf("RemoveSession() of a removed session error = %v", err)
+	}
+}
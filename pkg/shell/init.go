@@ -0,0 +1,50 @@
+package shell
+
+// bashInit runs kontext's hook before every bash prompt
+const bashInit = `# kontext shell integration for bash
+export KONTEXT_SESSION=$$
+_kontext_hook() {
+  local code=$?
+  eval "$(command kontext hook --shell bash)"
+  return $code
+}
+case ";${PROMPT_COMMAND:-};" in
+  *";_kontext_hook;"*) ;;
+  *) PROMPT_COMMAND="_kontext_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`
+
+// zshInit runs kontext's hook before every zsh prompt
+const zshInit = `# kontext shell integration for zsh
+export KONTEXT_SESSION=$$
+_kontext_hook() {
+  eval "$(command kontext hook --shell zsh)"
+}
+typeset -ag precmd_functions
+if (( ! ${precmd_functions[(I)_kontext_hook]} )); then
+  precmd_functions=(_kontext_hook $precmd_functions)
+fi
+`
+
+// fishInit runs kontext's hook before every fish prompt
+const fishInit = `# kontext shell integration for fish
+set -gx KONTEXT_SESSION $fish_pid
+function __kontext_hook --on-event fish_prompt
+    command kontext hook --shell fish | source
+end
+`
+
+// Init returns the shell integration script for a supported shell
+// It runs `kontext hook` before every prompt, which applies .kontext files
+// and the environment variables of the current context, so both follow
+// switches and directory changes.
+func Init(shell string) string {
+	switch shell {
+	case Fish:
+		return fishInit
+	case Zsh:
+		return zshInit
+	default:
+		return bashInit
+	}
+}
@@ -0,0 +1,107 @@
+// Package shell writes statements for the shells kontext integrates with
+//
+// kontext can't change the environment of the shell that runs it, so the
+// shell integration evaluates statements printed by kontext instead. This
+// package quotes values and renders export and unset statements for bash,
+// zsh and fish.
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManagedEnv lists the variables kontext exported, so they can be unset when the context changes
+const ManagedEnv = "KONTEXT_ENV_VARS"
+
+// SessionEnv identifies the shell running the integration, set to its PID by the init script
+const SessionEnv = "KONTEXT_SESSION"
+
+// Supported shells
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+)
+
+// Shells lists the supported shells
+var Shells = []string{Bash, Zsh, Fish}
+
+// Validate checks that a shell is supported
+func Validate(shell string) error {
+	for _, s := range Shells {
+		if s == shell {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported shell '%s', use %s", shell, strings.Join(Shells, ", "))
+}
+
+// Detect returns the user's shell from $SHELL, or bash if it isn't supported
+func Detect() string {
+	name := filepath.Base(os.Getenv("SHELL"))
+	if Validate(name) == nil {
+		return name
+	}
+	return Bash
+}
+
+// Quote returns s quoted so the shell reads it literally
+func Quote(shell, s string) string {
+	if shell == Fish {
+		s = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+		return "'" + s + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Export returns the statement exporting a variable
+func Export(shell, name, value string) string {
+	if shell == Fish {
+		return fmt.Sprintf("set -gx %s %s", name, Quote(shell, value))
+	}
+	return fmt.Sprintf("export %s=%s", name, Quote(shell, value))
+}
+
+// Unset returns the statement removing a variable
+func Unset(shell, name string) string {
+	if shell == Fish {
+		return fmt.Sprintf("set -e %s", name)
+	}
+	return "unset " + name
+}
+
+// EnvScript returns the statements replacing the previously exported variables with vars
+// previous is the value of ManagedEnv, a comma-separated list of names.
+// Variables in both are overwritten, the others are unset, and ManagedEnv is
+// updated to the new names.
+func EnvScript(shell, previous string, vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range strings.Split(previous, ",") {
+		if _, kept := vars[name]; name != "" && !kept {
+			lines = append(lines, Unset(shell, name))
+		}
+	}
+	for _, name := range names {
+		lines = append(lines, Export(shell, name, vars[name]))
+	}
+	if len(names) > 0 {
+		lines = append(lines, Export(shell, ManagedEnv, strings.Join(names, ",")))
+	} else if previous != "" {
+		lines = append(lines, Unset(shell, ManagedEnv))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		shell string
+		in    string
+		want  string
+	}{
+		{Bash, "plain", `'plain'`},
+		{Bash, "it's $HOME", `'it'\''s $HOME'`},
+		{Fish, `it's C:\dir`, `'it\'s C:\\dir'`},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.shell, tt.in); got != tt.want {
+			t.Errorf("Quote(%s, %q) = %s, want %s", tt.shell, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEnvScript(t *testing.T) {
+	vars := map[string]string{"AWS_PROFILE": "prod", "VAULT_ADDR": "https://vault"}
+
+	got := EnvScript(Bash, "AWS_PROFILE,ARGOCD_SERVER", vars)
+	want := `unset ARGOCD_SERVER
+export AWS_PROFILE='prod'
+export VAULT_ADDR='https://vault'
+export KONTEXT_ENV_VARS='AWS_PROFILE,VAULT_ADDR'
+`
+	if got != want {
+		t.Errorf("EnvScript(bash)\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = EnvScript(Fish, "AWS_PROFILE", nil)
+	want = "set -e AWS_PROFILE\nset -e KONTEXT_ENV_VARS\n"
+	if got != want {
+		t.Errorf("EnvScript(fish) without vars\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := EnvScript(Zsh, "", nil); got != "" {
+		t.Errorf("EnvScript() with nothing to do = %q", got)
+	}
+}
+
+func TestEnvScriptEvaluates(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	script := EnvScript(Bash, "", map[string]string{"KONTEXT_TEST_VALUE": `it's "quoted" $HOME`})
+	out, err := exec.Command("bash", "-c", script+`printf %s "$KONTEXT_TEST_VALUE"`).Output()
+	if err != nil {
+		t.Fatalf("bash error = %v", err)
+	}
+	if string(out) != `it's "quoted" $HOME` {
+		t.Errorf("bash read %q", out)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	original := os.Getenv("SHELL")
+	defer func() { _ = os.Setenv("SHELL", original) }()
+
+	_ = os.Setenv("SHELL", "/usr/bin/fish")
+	if got := Detect(); got != Fish {
+		t.Errorf("Detect() = %v, want fish", got)
+	}
+	_ = os.Setenv("SHELL", "/bin/tcsh")
+	if got := Detect(); got != Bash {
+		t.Errorf("Detect() with an unsupported shell = %v, want bash", got)
+	}
+}
+
+func TestInit(t *testing.T) {
+	for _, shell := range Shells {
+		got := Init(shell)
+		if !strings.Contains(got, "kontext hook --shell "+shell) {
+			t.Errorf("Init(%s) doesn't run the hook:\n%s", shell, got)
+		}
+		if !strings.Contains(got, SessionEnv) {
+			t.Errorf("Init(%s) doesn't set %s:\n%s", shell, SessionEnv, got)
+		}
+	}
+}
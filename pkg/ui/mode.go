@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// ErrNoInput is returned by interactive prompts when input is disabled
+var ErrNoInput = errors.New("interactive input is disabled (no terminal or --no-input)")
+
+// interactive reports whether prompts may be shown
+var interactive = true
+
+// SetInteractive enables or disables interactive prompts
+// When disabled, selectors and confirmations fail with ErrNoInput instead of
+// waiting for input that will never come.
+func SetInteractive(enabled bool) {
+	interactive = enabled
+}
+
+// IsInteractive reports whether interactive prompts are enabled
+func IsInteractive() bool {
+	return interactive
+}
+
+// selectorSize is the number of items interactive selectors show at once
+var selectorSize = 10
+
+// SetSelectorSize changes the number of items interactive selectors show at once
+func SetSelectorSize(size int) {
+	if size > 0 {
+		selectorSize = size
+	}
+}
+
+// IsTerminal reports whether both stdin and stdout are terminals
+func IsTerminal() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Glyphs are the symbols used to decorate output
+type Glyphs struct {
+	Success string
+	Error   string
+	Warning string
+	Info    string
+	Arrow   string
+	Rule    string
+}
+
+var (
+	// unicodeGlyphs are used on terminals
+	unicodeGlyphs = Glyphs{Success: "✓", Error: "✗", Warning: "!", Info: "ℹ", Arrow: "→", Rule: "─"}
+
+	// asciiGlyphs are used when unicode is disabled, for example in CI logs
+	asciiGlyphs = Glyphs{Success: "+", Error: "x", Warning: "!", Info: "i", Arrow: ">", Rule: "-"}
+)
+
+// SetASCII switches the default Printer between the unicode and the plain ASCII glyph set
+func SetASCII(ascii bool) {
+	WithASCII(ascii)(std)
+}
+
+// GetGlyphs returns the glyph set of the default Printer
+func GetGlyphs() Glyphs {
+	return std.Glyphs()
+}
+
+// SetColor enables or disables colored output, including in the default Printer
+func SetColor(enabled bool) {
+	color.NoColor = !enabled
+	WithColor(enabled)(std)
+}
+
+// ColorMode controls when output is colored
+type ColorMode string
+
+// Color modes
+const (
+	// ColorAuto colors output on terminals unless NO_COLOR is set or TERM is dumb
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors output even when it is redirected
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors output
+	ColorNever ColorMode = "never"
+)
+
+// ParseColorMode parses a color mode
+// An empty value is ColorAuto.
+func ParseColorMode(value string) (ColorMode, error) {
+	switch mode := ColorMode(strings.ToLower(value)); mode {
+	case "":
+		return ColorAuto, nil
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid color mode '%s': must be auto, always or never", value)
+	}
+}
+
+// SetColorMode applies a color mode
+// ColorAuto keeps the detected default, which already honors NO_COLOR.
+func SetColorMode(mode ColorMode) {
+	switch mode {
+	case ColorAlways:
+		SetColor(true)
+	case ColorNever:
+		SetColor(false)
+	}
+}
@@ -0,0 +1,371 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Verbosity controls how much a Printer prints
+type Verbosity int
+
+// Verbosity levels
+const (
+	// VerbosityQuiet prints only errors and the output a command was asked for
+	// (lists, status, structured output); status messages are suppressed
+	VerbosityQuiet Verbosity = -1
+	// VerbosityNormal is the default level
+	VerbosityNormal Verbosity = 0
+	// VerbosityVerbose also prints debug details such as the files loaded and API latency
+	VerbosityVerbose Verbosity = 1
+)
+
+// Printer writes formatted output
+//
+// Regular output goes to Out and diagnostics (errors, warnings and debug
+// messages) go to Err. A Printer has its own color policy, glyph set and
+// verbosity, so commands can be run against buffers in tests and embedded in
+// other programs.
+type Printer struct {
+	Out       io.Writer
+	Err       io.Writer
+	verbosity Verbosity
+	colors    *Colors
+	glyphs    Glyphs
+
+	colorEnabled bool
+	aliases      map[string][]string
+	groups       map[string]string
+	protected    func(contextName string) bool
+}
+
+// PrinterOption configures a Printer
+type PrinterOption func(*Printer)
+
+// WithColor enables or disables colored output
+// By default color is enabled when stdout is a terminal and NO_COLOR is unset.
+func WithColor(enabled bool) PrinterOption {
+	return func(p *Printer) {
+		p.colors = newColors(enabled)
+		p.colorEnabled = enabled
+	}
+}
+
+// WithASCII uses plain ASCII glyphs instead of unicode symbols
+func WithASCII(ascii bool) PrinterOption {
+	return func(p *Printer) {
+		if ascii {
+			p.glyphs = asciiGlyphs
+		} else {
+			p.glyphs = unicodeGlyphs
+		}
+	}
+}
+
+// WithVerbosity sets the verbosity level
+func WithVerbosity(v Verbosity) PrinterOption {
+	return func(p *Printer) {
+		p.verbosity = v
+	}
+}
+
+// NewPrinter creates a Printer writing output to out and diagnostics to errOut
+func NewPrinter(out, errOut io.Writer, opts ...PrinterOption) *Printer {
+	p := &Printer{
+		Out:          out,
+		Err:          errOut,
+		verbosity:    VerbosityNormal,
+		colors:       newColors(!color.NoColor),
+		glyphs:       unicodeGlyphs,
+		colorEnabled: !color.NoColor,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// std is the Printer used by the package-level Print functions
+var std = NewPrinter(os.Stdout, os.Stderr)
+
+// Default returns the Printer used by the package-level Print functions
+func Default() *Printer {
+	return std
+}
+
+// SetDefault replaces the Printer used by the package-level Print functions
+func SetDefault(p *Printer) {
+	std = p
+}
+
+// Verbosity returns the printer's verbosity level
+func (p *Printer) Verbosity() Verbosity {
+	return p.verbosity
+}
+
+// SetVerbosity changes the printer's verbosity level
+func (p *Printer) SetVerbosity(v Verbosity) {
+	p.verbosity = v
+}
+
+// Glyphs returns the printer's glyph set
+func (p *Printer) Glyphs() Glyphs {
+	return p.glyphs
+}
+
+// Colors returns the printer's color functions
+func (p *Printer) Colors() *Colors {
+	return p.colors
+}
+
+// SetAliases sets the context aliases shown next to context names
+// aliases maps each alias to the context it stands for.
+func (p *Printer) SetAliases(aliases map[string]string) {
+	p.aliases = make(map[string][]string)
+	for alias, context := range aliases {
+		p.aliases[context] = append(p.aliases[context], alias)
+	}
+	for _, names := range p.aliases {
+		sort.Strings(names)
+	}
+}
+
+// aliasLabel returns the label listing the aliases of a context, or "" if it has none
+func (p *Printer) aliasLabel(contextName string) string {
+	names := p.aliases[contextName]
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return " (alias: " + names[0] + ")"
+	default:
+		return " (aliases: " + strings.Join(names, ", ") + ")"
+	}
+}
+
+// SetGroups groups contexts under headings in context lists and selectors
+// groups maps context names to their group; contexts without one are listed last.
+// A nil map turns grouping off.
+func (p *Printer) SetGroups(groups map[string]string) {
+	p.groups = groups
+}
+
+// groupOrder returns the context names ordered by group, keeping their order within a group
+func (p *Printer) groupOrder(contextNames []string) []string {
+	ordered := make([]string, len(contextNames))
+	copy(ordered, contextNames)
+	if len(p.groups) == 0 {
+		return ordered
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		gi, iGrouped := p.groups[ordered[i]]
+		gj, jGrouped := p.groups[ordered[j]]
+		if iGrouped != jGrouped {
+			return iGrouped
+		}
+		return gi < gj
+	})
+	return ordered
+}
+
+// groupLabel returns the padded group column of a context for selectors, or "" without grouping
+func (p *Printer) groupLabel(contextName string) string {
+	if len(p.groups) == 0 {
+		return ""
+	}
+
+	width := len(ungrouped)
+	for _, group := range p.groups {
+		width = max(width, len(group))
+	}
+	group, exists := p.groups[contextName]
+	if !exists {
+		group = ungrouped
+	}
+	return fmt.Sprintf("%-*s  ", width, group)
+}
+
+// SetProtected sets the function reporting which contexts are protected
+// Protected contexts are rendered in the warning color with a badge. A nil
+// function marks no context as protected.
+func (p *Printer) SetProtected(protected func(contextName string) bool) {
+	p.protected = protected
+}
+
+// isProtected reports whether a context is protected
+func (p *Printer) isProtected(contextName string) bool {
+	return p.protected != nil && p.protected(contextName)
+}
+
+// protectedBadge returns the badge of a protected context, or "" for other contexts
+func (p *Printer) protectedBadge(contextName string) string {
+	if !p.isProtected(contextName) {
+		return ""
+	}
+	return " [" + p.glyphs.Warning + " protected]"
+}
+
+// ungrouped is the heading of contexts without a group
+const ungrouped = "(other)"
+
+// quiet reports whether status messages are suppressed
+func (p *Printer) quiet() bool {
+	return p.verbosity <= VerbosityQuiet
+}
+
+// Error prints a formatted error message to Err
+// If err is nil, only the message is displayed
+func (p *Printer) Error(msg string, err error) {
+	if err != nil {
+		fmt.Fprintf(p.Err, "%s %s: %v\n", p.colors.Red(p.glyphs.Error), msg, err)
+	} else {
+		fmt.Fprintf(p.Err, "%s %s\n", p.colors.Red(p.glyphs.Error), msg)
+	}
+}
+
+// Success prints a formatted success message
+func (p *Printer) Success(msg string, details ...string) {
+	if p.quiet() {
+		return
+	}
+	fmt.Fprintf(p.Out, "%s %s", p.colors.Green(p.glyphs.Success), msg)
+
+	for _, detail := range details {
+		fmt.Fprintf(p.Out, " %s", p.colors.Cyan(detail))
+	}
+	fmt.Fprintln(p.Out)
+}
+
+// Warning prints a formatted warning message to Err
+func (p *Printer) Warning(msg string, details ...string) {
+	if p.quiet() {
+		return
+	}
+	fmt.Fprintf(p.Err, "%s %s", p.colors.Yellow(p.glyphs.Warning), msg)
+
+	for _, detail := range details {
+		fmt.Fprintf(p.Err, " %s", p.colors.Cyan(detail))
+	}
+	fmt.Fprintln(p.Err)
+}
+
+// Info prints a formatted information label and value
+func (p *Printer) Info(label string, value string) {
+	fmt.Fprintf(p.Out, "%s: %s\n", p.colors.Bold(label), value)
+}
+
+// Note prints a formatted note message with an info icon
+func (p *Printer) Note(msg string, details ...string) {
+	if p.quiet() {
+		return
+	}
+	fmt.Fprintf(p.Out, "%s %s", p.colors.Blue(p.glyphs.Info), p.colors.Blue("Note:"))
+
+	fmt.Fprintf(p.Out, " %s", msg)
+
+	for _, detail := range details {
+		fmt.Fprintf(p.Out, " %s", p.colors.Cyan(detail))
+	}
+	fmt.Fprintln(p.Out)
+}
+
+// Debugf prints a debug message to Err when the printer is verbose
+func (p *Printer) Debugf(format string, args ...interface{}) {
+	if p.verbosity < VerbosityVerbose {
+		return
+	}
+	fmt.Fprintf(p.Err, "%s %s\n", p.colors.Faint("debug:"), fmt.Sprintf(format, args...))
+}
+
+// CurrentContext displays the current context information
+func (p *Printer) CurrentContext(contextName string) {
+	fmt.Fprintf(p.Out, "%s %s %s\n", p.colors.Green(p.glyphs.Arrow), p.colors.Bold("Current context:"), p.colors.Cyan(contextName))
+}
+
+// CurrentNamespace displays the current namespace information for a context
+func (p *Printer) CurrentNamespace(contextName, namespaceName string) {
+	fmt.Fprintf(p.Out, "%s %s %s\n", p.colors.Green(p.glyphs.Arrow), p.colors.Bold(fmt.Sprintf("Context: %s Namespace:", contextName)), p.colors.Cyan(namespaceName))
+}
+
+// ContextList displays a formatted list of available contexts
+func (p *Printer) ContextList(contextNames []string, currentContext string) {
+	// Print header
+	fmt.Fprintln(p.Out, p.colors.Bold("Available Kubernetes contexts:"))
+	fmt.Fprintln(p.Out, p.colors.Faint(strings.Repeat(p.glyphs.Rule, 35)))
+
+	// Print contexts, under a heading per group when grouping is on
+	heading := ""
+	for _, name := range p.groupOrder(contextNames) {
+		if len(p.groups) > 0 {
+			group, exists := p.groups[name]
+			if !exists {
+				group = ungrouped
+			}
+			if group != heading {
+				heading = group
+				fmt.Fprintln(p.Out, p.colors.Bold(heading))
+			}
+		}
+
+		aliases := p.colors.Faint(p.aliasLabel(name))
+		badge := p.colors.Yellow(p.protectedBadge(name))
+		label := name
+		if p.isProtected(name) {
+			label = p.colors.Yellow(name)
+		} else if name == currentContext {
+			label = p.colors.Cyan(name)
+		}
+		if name == currentContext {
+			fmt.Fprintf(p.Out, "%s %s%s%s %s\n", p.colors.Green(p.glyphs.Arrow), label, aliases, badge, p.colors.Green("(current)"))
+		} else {
+			fmt.Fprintf(p.Out, "  %s%s%s\n", label, aliases, badge)
+		}
+	}
+}
+
+// Section displays a section heading with an optional highlighted name
+func (p *Printer) Section(title string, name string, details ...string) {
+	fmt.Fprintf(p.Out, "%s %s", p.colors.Bold(title+":"), p.colors.Cyan(name))
+
+	for _, detail := range details {
+		fmt.Fprintf(p.Out, " %s", p.colors.Green(detail))
+	}
+	fmt.Fprintln(p.Out)
+}
+
+// Field displays an indented label and value inside a section
+// Empty values are skipped.
+func (p *Printer) Field(label string, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(p.Out, "  %s %s\n", p.colors.Faint(fmt.Sprintf("%-24s", label+":")), value)
+}
+
+// List displays a header followed by an indented list of items
+func (p *Printer) List(header string, items []string) {
+	fmt.Fprintln(p.Out, p.colors.Bold(header))
+	for _, item := range items {
+		fmt.Fprintf(p.Out, "  %s\n", item)
+	}
+}
+
+// Issue displays a single validation issue with an icon matching its severity
+func (p *Printer) Issue(severity, subject, message, code string) {
+	var icon string
+	switch severity {
+	case "error":
+		icon = p.colors.Red(p.glyphs.Error)
+	case "warning":
+		icon = p.colors.Yellow(p.glyphs.Warning)
+	default:
+		icon = p.colors.Blue(p.glyphs.Info)
+	}
+
+	fmt.Fprintf(p.Out, "%s %s: %s %s\n", icon, p.colors.Bold(subject), message, p.colors.Faint("("+code+")"))
+}
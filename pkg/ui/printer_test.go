@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newTestPrinter returns a plain Printer writing to buffers
+func newTestPrinter(opts ...PrinterOption) (*Printer, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	opts = append([]PrinterOption{WithColor(false), WithASCII(true)}, opts...)
+	return NewPrinter(&out, &errOut, opts...), &out, &errOut
+}
+
+func TestPrinterGolden(t *testing.T) {
+	p, out, errOut := newTestPrinter()
+
+	p.Success("Switched to context", "prod")
+	p.Warning("Namespace 'team' is already selected")
+	p.Error("Error switching context", errors.New("context not found: 'x'"))
+	p.Note("Dry run, nothing was deleted")
+	p.CurrentNamespace("prod", "payments")
+	p.ContextList([]string{"dev", "prod"}, "prod")
+	p.Section("Cluster", "prod-cluster", "(missing)")
+	p.Field("Server", "https://prod.example.com")
+	p.Field("Proxy URL", "")
+	p.Issue("error", "context prod", "references cluster 'prod' which does not exist", "context-missing-cluster")
+	p.Debugf("not printed at normal verbosity")
+
+	wantOut := `+ Switched to context prod
+i Note: Dry run, nothing was deleted
+> Context: prod Namespace: payments
+Available Kubernetes contexts:
+-----------------------------------
+  dev
+> prod (current)
+Cluster: prod-cluster (missing)
+  Server:                  https://prod.example.com
+x context prod: references cluster 'prod' which does not exist (context-missing-cluster)
+`
+	wantErr := `! Namespace 'team' is already selected
+x Error switching context: context not found: 'x'
+`
+
+	if out.String() != wantOut {
+		t.Errorf("stdout mismatch\ngot:\n%s\nwant:\n%s", out.String(), wantOut)
+	}
+	if errOut.String() != wantErr {
+		t.Errorf("stderr mismatch\ngot:\n%s\nwant:\n%s", errOut.String(), wantErr)
+	}
+}
+
+func TestPrinterVerbosity(t *testing.T) {
+	tests := []struct {
+		name      string
+		verbosity Verbosity
+		wantOut   string
+		wantErr   string
+	}{
+		{
+			name:      "Quiet keeps errors and requested output",
+			verbosity: VerbosityQuiet,
+			wantOut:   "> Current context: prod\n",
+			wantErr:   "x failed\n",
+		},
+		{
+			name:      "Normal",
+			verbosity: VerbosityNormal,
+			wantOut:   "+ done\n> Current context: prod\n",
+			wantErr:   "! careful\nx failed\n",
+		},
+		{
+			name:      "Verbose adds debug messages",
+			verbosity: VerbosityVerbose,
+			wantOut:   "+ done\n> Current context: prod\n",
+			wantErr:   "! careful\nx failed\ndebug: loaded 3 files\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, out, errOut := newTestPrinter(WithVerbosity(tt.verbosity))
+
+			p.Success("done")
+			p.Warning("careful")
+			p.Error("failed", nil)
+			p.CurrentContext("prod")
+			p.Debugf("loaded %d files", 3)
+
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if errOut.String() != tt.wantErr {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrinterColor(t *testing.T) {
+	var out bytes.Buffer
+	NewPrinter(&out, &out, WithColor(true)).Success("done")
+	if !bytes.Contains(out.Bytes(), []byte("\x1b[")) {
+		t.Errorf("WithColor(true) output has no color codes: %q", out.String())
+	}
+}
+
+func TestPrinterAliases(t *testing.T) {
+	p, out, _ := newTestPrinter()
+	p.SetAliases(map[string]string{"p": "prod", "prd": "prod", "d": "dev"})
+
+	p.ContextList([]string{"dev", "prod", "staging"}, "prod")
+
+	want := `Available Kubernetes contexts:
+-----------------------------------
+  dev (alias: d)
+> prod (aliases: p, prd) (current)
+  staging
+`
+	if out.String() != want {
+		t.Errorf("ContextList() with aliases\ngot:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	templates := p.selectTemplates("Select Kubernetes Context:", "{{ . }}{{ aliases . | faint }}", "{{ . }}", "", "")
+	if got := renderTemplate(t, templates.Active, templates.FuncMap, "dev"); got != "> dev (alias: d)" {
+		t.Errorf("Active template with aliases rendered %q", got)
+	}
+}
+
+func TestPrinterGroups(t *testing.T) {
+	p, out, _ := newTestPrinter()
+	p.SetGroups(map[string]string{"prod-eu": "env=prod", "dev": "env=dev", "prod-us": "env=prod"})
+
+	p.ContextList([]string{"dev", "local", "prod-eu", "prod-us"}, "dev")
+
+	want := `Available Kubernetes contexts:
+-----------------------------------
+env=dev
+> dev (current)
+env=prod
+  prod-eu
+  prod-us
+(other)
+  local
+`
+	if out.String() != want {
+		t.Errorf("ContextList() with groups\ngot:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	templates := p.selectTemplates("Select Kubernetes Context:", "{{ group . }}{{ . }}", "{{ . }}", "", "")
+	if got := renderTemplate(t, templates.Active, templates.FuncMap, "local"); got != "> (other)   local" {
+		t.Errorf("Active template with groups rendered %q", got)
+	}
+}
+
+func TestPrinterProtected(t *testing.T) {
+	p, out, _ := newTestPrinter()
+	p.SetProtected(func(name string) bool { return strings.HasPrefix(name, "prod") })
+
+	p.ContextList([]string{"dev", "prod"}, "prod")
+
+	want := `Available Kubernetes contexts:
+-----------------------------------
+  dev
+> prod [! protected] (current)
+`
+	if out.String() != want {
+		t.Errorf("ContextList() with protected contexts\ngot:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	templates := p.selectTemplates("Select Kubernetes Context:", "{{ . }}{{ badge . }}", "{{ . }}", "", "")
+	if got := renderTemplate(t, templates.Active, templates.FuncMap, "prod-eu"); got != "> prod-eu [! protected]" {
+		t.Errorf("Active template with a protected context rendered %q", got)
+	}
+	if got := renderTemplate(t, templates.Active, templates.FuncMap, "dev"); got != "> dev" {
+		t.Errorf("Active template with an unprotected context rendered %q", got)
+	}
+}
+
+func TestPrinterSegment(t *testing.T) {
+	p, _, _ := newTestPrinter()
+	p.SetProtected(func(name string) bool { return name == "prod" })
+
+	if got := p.Segment("dev", "apps"); got != "dev:apps" {
+		t.Errorf("Segment(dev) = %q", got)
+	}
+	if got := p.Segment("prod", ""); got != "prod [! protected]" {
+		t.Errorf("Segment(prod) = %q", got)
+	}
+}
+
+func TestWrapEscapes(t *testing.T) {
+	colored := "\x1b[33mprod\x1b[0m:100%"
+
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", "\x01\x1b[33m\x02prod\x01\x1b[0m\x02:100%"},
+		{"zsh", "%{\x1b[33m%}prod%{\x1b[0m%}:100%%"},
+		{"", colored},
+	}
+	for _, tt := range tests {
+		if got := WrapEscapes(colored, tt.shell); got != tt.want {
+			t.Errorf("WrapEscapes(%q) = %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Segment returns the shell prompt segment for a context and namespace
+// Protected contexts are rendered in the warning color with a badge.
+func (p *Printer) Segment(contextName, namespace string) string {
+	name := p.colors.Cyan(contextName)
+	if p.isProtected(contextName) {
+		name = p.colors.Yellow(contextName) + p.colors.Yellow(p.protectedBadge(contextName))
+	}
+	if namespace == "" {
+		return name
+	}
+	return fmt.Sprintf("%s%s%s", name, p.colors.Faint(":"), namespace)
+}
+
+// ansiEscape matches the color escape sequences written by Colors
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// WrapEscapes marks color escape sequences as zero-width for a shell prompt
+// Without the markers bash and zsh miscount the prompt's width and garble
+// line editing. Other shells get s unchanged.
+func WrapEscapes(s, shell string) string {
+	switch shell {
+	case "bash":
+		return ansiEscape.ReplaceAllString(s, "\x01$0\x02")
+	case "zsh":
+		s = strings.ReplaceAll(s, "%", "%%")
+		return ansiEscape.ReplaceAllString(s, "%{$0%}")
+	}
+	return s
+}
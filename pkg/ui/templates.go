@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+)
+
+// templateFuncs returns the functions available in prompt templates
+// promptui always emits ANSI codes, so the color functions become plain text
+// when the printer's colors are disabled. aliases and group return the alias
+// label and the group column of a context name, protected reports whether a
+// context is protected and badge returns its protected badge.
+func (p *Printer) templateFuncs() template.FuncMap {
+	plain := func(v interface{}) string {
+		return fmt.Sprint(v)
+	}
+
+	funcs := make(template.FuncMap, len(promptui.FuncMap)+4)
+	for name, fn := range promptui.FuncMap {
+		if p.colorEnabled {
+			funcs[name] = fn
+		} else {
+			funcs[name] = plain
+		}
+	}
+	funcs["aliases"] = p.aliasLabel
+	funcs["group"] = p.groupLabel
+	funcs["protected"] = p.isProtected
+	funcs["badge"] = p.protectedBadge
+	return funcs
+}
+
+// selectTemplates returns selector templates using the printer's glyphs and colors
+// active, inactive and selected describe how an item is rendered; hint is shown below the list.
+func (p *Printer) selectTemplates(label, active, inactive, selected, hint string) *promptui.SelectTemplates {
+	return &promptui.SelectTemplates{
+		Label:    "{{ " + quote(label) + " | bold }}",
+		Active:   "{{ " + quote(p.glyphs.Arrow) + " | cyan | bold }} " + active,
+		Inactive: "  " + inactive,
+		Selected: selected,
+		Details:  "{{ " + quote(strings.Repeat(p.glyphs.Rule, 39)) + " | faint }}\n{{ " + quote("  "+hint) + " | faint }}",
+		Help:     `{{ "Use the arrow keys to navigate" | faint }}`,
+		FuncMap:  p.templateFuncs(),
+	}
+}
+
+// promptTemplates returns confirmation prompt templates using the printer's glyphs and colors
+func (p *Printer) promptTemplates() *promptui.PromptTemplates {
+	return &promptui.PromptTemplates{
+		Confirm: `{{ "?" | blue | bold }} {{ . | bold }}? {{ "[y/N]" | faint }} `,
+		Prompt:  `{{ "?" | blue | bold }} {{ . | bold }}: `,
+		Valid:   "{{ " + quote(p.glyphs.Success) + " | green | bold }} {{ . | bold }}: ",
+		Invalid: "{{ " + quote(p.glyphs.Error) + " | red | bold }} {{ . | bold }}: ",
+		Success: `{{ . | faint }}: `,
+		FuncMap: p.templateFuncs(),
+	}
+}
+
+// quote returns s as a template string literal
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// renderTemplate renders a prompt template with the given funcs
+func renderTemplate(t *testing.T, text string, funcs template.FuncMap, data interface{}) string {
+	t.Helper()
+	tpl, err := template.New("").Funcs(funcs).Parse(text)
+	if err != nil {
+		t.Fatalf("Failed to parse template %q: %v", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		t.Fatalf("Failed to render template %q: %v", text, err)
+	}
+	return buf.String()
+}
+
+// isPlainASCII reports whether s has neither escape codes nor non-ASCII characters
+func isPlainASCII(s string) bool {
+	for _, r := range s {
+		if r > 127 || r == '\x1b' {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSelectTemplatesASCII(t *testing.T) {
+	p := NewPrinter(&bytes.Buffer{}, &bytes.Buffer{}, WithColor(false), WithASCII(true))
+	templates := p.selectTemplates(
+		"Select Kubernetes Context:",
+		"{{ . | cyan | bold }}{{ if eq . \"prod\" }} {{ \"(current)\" | green | bold }}{{ end }}",
+		"{{ . }}",
+		"{{ "+quote(p.glyphs.Success)+" | green | bold }} {{ . | cyan | bold }}",
+		"Use arrow keys to navigate and Enter to select",
+	)
+
+	for name, text := range map[string]string{
+		"Label":    templates.Label,
+		"Active":   templates.Active,
+		"Inactive": templates.Inactive,
+		"Selected": templates.Selected,
+		"Details":  templates.Details,
+		"Help":     templates.Help,
+	} {
+		got := renderTemplate(t, text, templates.FuncMap, "prod")
+		if !isPlainASCII(got) {
+			t.Errorf("%s template rendered %q, want plain ASCII", name, got)
+		}
+	}
+
+	if got := renderTemplate(t, templates.Active, templates.FuncMap, "prod"); got != "> prod (current)" {
+		t.Errorf("Active template rendered %q", got)
+	}
+	if got := renderTemplate(t, templates.Details, templates.FuncMap, "prod"); !strings.HasPrefix(got, strings.Repeat("-", 39)) {
+		t.Errorf("Details template rendered %q", got)
+	}
+}
+
+func TestPromptTemplatesColor(t *testing.T) {
+	plain := NewPrinter(&bytes.Buffer{}, &bytes.Buffer{}, WithColor(false), WithASCII(true)).promptTemplates()
+	if got := renderTemplate(t, plain.Confirm, plain.FuncMap, "Delete context"); got != "? Delete context? [y/N] " {
+		t.Errorf("Confirm template rendered %q", got)
+	}
+	if got := renderTemplate(t, plain.Valid, plain.FuncMap, "Delete context"); !isPlainASCII(got) {
+		t.Errorf("Valid template rendered %q, want plain ASCII", got)
+	}
+
+	colored := NewPrinter(&bytes.Buffer{}, &bytes.Buffer{}, WithColor(true)).promptTemplates()
+	if got := renderTemplate(t, colored.Confirm, colored.FuncMap, "Delete context"); !strings.Contains(got, "\x1b[") {
+		t.Errorf("Confirm template rendered %q, want color codes", got)
+	}
+}
+
+func TestNamespaceSelectorAt(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+	SetDefault(NewPrinter(&bytes.Buffer{}, &bytes.Buffer{}, WithColor(false), WithASCII(true)))
+
+	namespaces := []string{"default", "orders", "payments"}
+	selector := CreateNamespaceSelectorAt(namespaces, "default", "prod", "payments")
+	if selector.CursorPos != 2 {
+		t.Errorf("CursorPos = %d, want 2 (payments)", selector.CursorPos)
+	}
+
+	templates := selector.Templates
+	if got := renderTemplate(t, templates.Inactive, templates.FuncMap, "payments"); got != "  payments (last used)" {
+		t.Errorf("Inactive template rendered %q for the last used namespace", got)
+	}
+	if got := renderTemplate(t, templates.Inactive, templates.FuncMap, "default"); got != "  default (current)" {
+		t.Errorf("Inactive template rendered %q for the current namespace", got)
+	}
+
+	selector = CreateNamespaceSelector(namespaces, "orders", "prod")
+	if selector.CursorPos != 1 {
+		t.Errorf("CursorPos = %d, want 1 (the current namespace)", selector.CursorPos)
+	}
+	if got := renderTemplate(t, selector.Templates.Inactive, selector.Templates.FuncMap, "payments"); strings.Contains(got, "last used") {
+		t.Errorf("Inactive template rendered %q, want no last used marker", got)
+	}
+}
@@ -7,114 +7,137 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"sort"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
 )
 
+// ErrSelectionCanceled is returned when the user cancels an interactive prompt
+// with Ctrl+C or Ctrl+D
+var ErrSelectionCanceled = errors.New("selection canceled")
+
+// canceled maps promptui's interrupt errors to ErrSelectionCanceled
+func canceled(err error) error {
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return ErrSelectionCanceled
+	}
+	return err
+}
+
 // Colors creates and returns commonly used colored print functions
 type Colors struct {
 	Red    func(a ...interface{}) string
 	Green  func(a ...interface{}) string
 	Yellow func(a ...interface{}) string
+	Blue   func(a ...interface{}) string
 	Cyan   func(a ...interface{}) string
 	Bold   func(a ...interface{}) string
 	Faint  func(a ...interface{}) string
 }
 
 // NewColors returns initialized color functions for consistent UI styling
+// Colors are disabled when stdout is not a terminal or NO_COLOR is set.
 func NewColors() *Colors {
+	return newColors(!color.NoColor)
+}
+
+// newColors returns color functions that are either always or never colored
+func newColors(enabled bool) *Colors {
+	sprint := func(attributes ...color.Attribute) func(a ...interface{}) string {
+		c := color.New(attributes...)
+		if enabled {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+		return c.SprintFunc()
+	}
+
 	return &Colors{
-		Red:    color.New(color.FgRed, color.Bold).SprintFunc(),
-		Green:  color.New(color.FgGreen, color.Bold).SprintFunc(),
-		Yellow: color.New(color.FgYellow, color.Bold).SprintFunc(),
-		Cyan:   color.New(color.FgCyan, color.Bold).SprintFunc(),
-		Bold:   color.New(color.Bold).SprintFunc(),
-		Faint:  color.New(color.Faint).SprintFunc(),
+		Red:    sprint(color.FgRed, color.Bold),
+		Green:  sprint(color.FgGreen, color.Bold),
+		Yellow: sprint(color.FgYellow, color.Bold),
+		Blue:   sprint(color.FgBlue, color.Bold),
+		Cyan:   sprint(color.FgCyan, color.Bold),
+		Bold:   sprint(color.Bold),
+		Faint:  sprint(color.Faint),
 	}
 }
 
-// PrintError prints a formatted error message and exits if exitOnError is true
+// PrintError prints a formatted error message to stderr
 // If err is nil, only the message is displayed
-func PrintError(msg string, err error, exitOnError bool) {
-	colors := NewColors()
-	if err != nil {
-		fmt.Printf("%s %s: %v\n", colors.Red("✗"), msg, err)
-	} else {
-		fmt.Printf("%s %s\n", colors.Red("✗"), msg)
-	}
-	if exitOnError {
-		os.Exit(1)
-	}
+func PrintError(msg string, err error) {
+	std.Error(msg, err)
 }
 
 // PrintSuccess prints a formatted success message
 func PrintSuccess(msg string, details ...string) {
-	colors := NewColors()
-	fmt.Printf("%s %s", colors.Green("✓"), msg)
-
-	for _, detail := range details {
-		fmt.Printf(" %s", colors.Cyan(detail))
-	}
-	fmt.Println()
+	std.Success(msg, details...)
 }
 
-// PrintWarning prints a formatted warning message
+// PrintWarning prints a formatted warning message to stderr
 func PrintWarning(msg string, details ...string) {
-	colors := NewColors()
-	fmt.Printf("%s %s", colors.Yellow("!"), msg)
-
-	for _, detail := range details {
-		fmt.Printf(" %s", colors.Cyan(detail))
-	}
-	fmt.Println()
+	std.Warning(msg, details...)
 }
 
 // PrintInfo prints a formatted information label and value
 func PrintInfo(label string, value string) {
-	colors := NewColors()
-	fmt.Printf("%s: %s\n", colors.Bold(label), value)
+	std.Info(label, value)
 }
 
 // PrintNote prints a formatted note message with an info icon
 func PrintNote(msg string, details ...string) {
-	colors := NewColors()
-	// Using blue color with info icon for notes
-	blue := color.New(color.FgBlue, color.Bold).SprintFunc()
-	fmt.Printf("%s %s", blue("ℹ"), blue("Note:"))
+	std.Note(msg, details...)
+}
 
-	fmt.Printf(" %s", msg)
+// Debugf prints a debug message to stderr in verbose mode
+func Debugf(format string, args ...interface{}) {
+	std.Debugf(format, args...)
+}
 
-	for _, detail := range details {
-		fmt.Printf(" %s", colors.Cyan(detail))
-	}
-	fmt.Println()
+// SetContextAliases sets the context aliases shown by the default Printer and the context selector
+// aliases maps each alias to the context it stands for.
+func SetContextAliases(aliases map[string]string) {
+	std.SetAliases(aliases)
+}
+
+// SetContextGroups groups contexts under headings in the default Printer and the context selector
+// groups maps context names to their group; a nil map turns grouping off.
+func SetContextGroups(groups map[string]string) {
+	std.SetGroups(groups)
+}
+
+// SetProtectedContexts sets the function reporting which contexts the default Printer
+// and the context selector render as protected
+func SetProtectedContexts(protected func(contextName string) bool) {
+	std.SetProtected(protected)
 }
 
 // PrintCurrentContext displays the current context information
 func PrintCurrentContext(contextName string) {
-	colors := NewColors()
-	fmt.Printf("%s %s %s\n", colors.Green("→"), colors.Bold("Current context:"), colors.Cyan(contextName))
+	std.CurrentContext(contextName)
 }
 
 // PrintCurrentNamespace displays the current namespace information for a context
 func PrintCurrentNamespace(contextName, namespaceName string) {
-	colors := NewColors()
-	fmt.Printf("%s %s %s\n", colors.Green("→"), colors.Bold(fmt.Sprintf("Context: %s Namespace:", contextName)), colors.Cyan(namespaceName))
+	std.CurrentNamespace(contextName, namespaceName)
 }
 
 // CreateContextSelector creates an interactive prompt UI for selecting Kubernetes contexts
+// When contexts are grouped, they are ordered by group and the group is shown in front of each name.
 func CreateContextSelector(contexts []string, currentContext string) *promptui.Select {
-	templates := &promptui.SelectTemplates{
-		Label:    "{{ \"Select Kubernetes Context:\" | bold }}",
-		Active:   "{{ \"→\" | cyan | bold }} {{ . | cyan | bold }}{{ if eq . \"" + currentContext + "\" }} {{ \"(current)\" | green | bold }}{{ end }}",
-		Inactive: "  {{ . }}{{ if eq . \"" + currentContext + "\" }} {{ \"(current)\" | green }}{{ end }}",
-		Selected: "{{ \"✓\" | green | bold }} {{ \"Selected context:\" | bold }} {{ . | cyan | bold }}",
-		Details:  "{{ \"───────────────────────────────────────\" | faint }}\n{{ \"  Use arrow keys to navigate and Enter to select\" | faint }}",
-	}
+	contexts = std.groupOrder(contexts)
+	templates := std.selectTemplates(
+		"Select Kubernetes Context:",
+		"{{ group . | faint }}{{ if protected . }}{{ . | yellow | bold }}{{ else }}{{ . | cyan | bold }}{{ end }}{{ aliases . | faint }}{{ badge . | yellow | bold }}{{ if eq . "+quote(currentContext)+" }} {{ \"(current)\" | green | bold }}{{ end }}",
+		"{{ group . | faint }}{{ if protected . }}{{ . | yellow }}{{ else }}{{ . }}{{ end }}{{ aliases . | faint }}{{ badge . | yellow }}{{ if eq . "+quote(currentContext)+" }} {{ \"(current)\" | green }}{{ end }}",
+		"{{ "+quote(std.glyphs.Success)+" | green | bold }} {{ \"Selected context:\" | bold }} {{ . | cyan | bold }}",
+		"Use arrow keys to navigate and Enter to select",
+	)
 
 	cursorPos := 0
 	for i, ctx := range contexts {
@@ -126,46 +149,71 @@ func CreateContextSelector(contexts []string, currentContext string) *promptui.S
 
 	// Log statement to help debugging cursor position issues
 	if cursorPos == 0 && currentContext != "" && len(contexts) > 0 && contexts[0] != currentContext {
-		PrintNote(fmt.Sprintf("Current context '%s' not found in sorted context list, defaulting to first item", currentContext))
+		Debugf("Current context '%s' not found in sorted context list, defaulting to first item", currentContext)
 	}
 
 	return &promptui.Select{
 		Label:     "Context",
 		Items:     contexts,
 		Templates: templates,
-		Size:      10,
+		Size:      selectorSize,
 		CursorPos: cursorPos,
 	}
 }
 
+// RunSelector runs an interactive selector and returns the selected item
+// It returns ErrSelectionCanceled if the user cancels the selection and
+// ErrNoInput if interactive prompts are disabled.
+func RunSelector(selector *promptui.Select) (string, error) {
+	if !interactive {
+		return "", ErrNoInput
+	}
+
+	_, selection, err := selector.Run()
+	if err != nil {
+		return "", canceled(err)
+	}
+	return selection, nil
+}
+
 // CreateNamespaceSelector creates an interactive prompt UI for selecting Kubernetes namespaces
 func CreateNamespaceSelector(namespaces []string, currentNamespace string, currentContext string) *promptui.Select {
-	templates := &promptui.SelectTemplates{
-		Label:    "{{ \"Select Namespace:\" | bold }}",
-		Active:   "{{ \"→\" | cyan | bold }} {{ . | cyan | bold }}{{ if eq . \"" + currentNamespace + "\" }} {{ \"(current)\" | green | bold }}{{ end }}",
-		Inactive: "  {{ . }}{{ if eq . \"" + currentNamespace + "\" }} {{ \"(current)\" | green }}{{ end }}",
-		Selected: "{{ \"✓\" | green | bold }} {{ \"Context:\" | bold }} {{ \"" + currentContext + "\" | cyan | bold }} {{ \"Namespace:\" | bold }} {{ . | cyan | bold }}",
-		Details:  "{{ \"───────────────────────────────────────\" | faint }}\n{{ \"  Use arrow keys to navigate and Enter to select\" | faint }}",
+	return CreateNamespaceSelectorAt(namespaces, currentNamespace, currentContext, currentNamespace)
+}
+
+// CreateNamespaceSelectorAt is like CreateNamespaceSelector but places the cursor on
+// cursorNamespace, such as the namespace last used in the context, marking it "(last used)"
+func CreateNamespaceSelectorAt(namespaces []string, currentNamespace, currentContext, cursorNamespace string) *promptui.Select {
+	lastUsed := ""
+	if cursorNamespace != currentNamespace {
+		lastUsed = "{{ if eq . " + quote(cursorNamespace) + " }} {{ \"(last used)\" | faint }}{{ end }}"
 	}
+	templates := std.selectTemplates(
+		"Select Namespace:",
+		"{{ . | cyan | bold }}{{ if eq . "+quote(currentNamespace)+" }} {{ \"(current)\" | green | bold }}{{ end }}"+lastUsed,
+		"{{ . }}{{ if eq . "+quote(currentNamespace)+" }} {{ \"(current)\" | green }}{{ end }}"+lastUsed,
+		"{{ "+quote(std.glyphs.Success)+" | green | bold }} {{ \"Context:\" | bold }} {{ "+quote(currentContext)+" | cyan | bold }} {{ \"Namespace:\" | bold }} {{ . | cyan | bold }}",
+		"Use arrow keys to navigate and Enter to select",
+	)
 
 	cursorPos := 0
 	for i, ns := range namespaces {
-		if ns == currentNamespace {
+		if ns == cursorNamespace {
 			cursorPos = i
 			break
 		}
 	}
 
 	// Log statement to help debugging cursor position issues
-	if cursorPos == 0 && currentNamespace != "" && len(namespaces) > 0 && namespaces[0] != currentNamespace {
-		PrintNote(fmt.Sprintf("Current namespace '%s' not found in sorted namespace list, defaulting to first item", currentNamespace))
+	if cursorPos == 0 && cursorNamespace != "" && len(namespaces) > 0 && namespaces[0] != cursorNamespace {
+		Debugf("Namespace '%s' not found in sorted namespace list, defaulting to first item", cursorNamespace)
 	}
 
 	return &promptui.Select{
 		Label:     "Namespace",
 		Items:     namespaces,
 		Templates: templates,
-		Size:      10,
+		Size:      selectorSize,
 		CursorPos: cursorPos,
 	}
 }
@@ -201,7 +249,7 @@ func SortContexts(contextNames []string, currentContext string, prioritizeCurren
 			}
 		} // If current context wasn't found in the list, log a debug message
 		if !found {
-			PrintNote(fmt.Sprintf("Current context '%s' not found in context list", currentContext))
+			Debugf("Current context '%s' not found in context list", currentContext)
 		}
 	}
 
@@ -239,7 +287,7 @@ func SortNamespaces(namespaces []string, currentNamespace string, prioritizeCurr
 			}
 		} // If current namespace wasn't found in the list, log a debug message
 		if !found {
-			PrintNote(fmt.Sprintf("Current namespace '%s' not found in namespace list", currentNamespace))
+			Debugf("Current namespace '%s' not found in namespace list", currentNamespace)
 		}
 	}
 
@@ -255,28 +303,125 @@ func SortNamespaces(namespaces []string, currentNamespace string, prioritizeCurr
 //	staging
 //	→ production (current)
 func PrintContextList(contextNames []string, currentContext string) {
-	colors := NewColors()
+	std.ContextList(contextNames, currentContext)
+}
 
-	// Print header
-	fmt.Println(colors.Bold("Available Kubernetes contexts:"))
-	fmt.Println(colors.Faint("───────────────────────────────────"))
+// PrintSection displays a section heading with an optional highlighted name
+// Output:
+//
+//	Cluster: prod-cluster
+func PrintSection(title string, name string, details ...string) {
+	std.Section(title, name, details...)
+}
 
-	// Print contexts
-	for _, name := range contextNames {
-		if name == currentContext {
-			fmt.Printf("%s %s %s\n", colors.Green("→"), colors.Cyan(name), colors.Green("(current)"))
-		} else {
-			fmt.Printf("  %s\n", name)
+// PrintField displays an indented label and value inside a section
+// Empty values are skipped.
+func PrintField(label string, value string) {
+	std.Field(label, value)
+}
+
+// PrintList displays a header followed by an indented list of items
+func PrintList(header string, items []string) {
+	std.List(header, items)
+}
+
+// PrintIssue displays a single validation issue with an icon matching its severity
+// Output:
+//
+//	✗ context prod: references cluster 'prod' which does not exist (context-missing-cluster)
+func PrintIssue(severity, subject, message, code string) {
+	std.Issue(severity, subject, message, code)
+}
+
+// multiSelectItem is a single row of the multi-select checklist
+type multiSelectItem struct {
+	Label    string
+	Selected bool
+	Done     bool
+}
+
+// MultiSelect shows an interactive checklist and returns the indices of the checked items
+//
+// Enter toggles the highlighted item; choosing "Done" confirms the selection.
+// All items start checked when preselected is true. It returns ErrNoInput if
+// interactive prompts are disabled.
+func MultiSelect(label string, items []string, preselected bool) ([]int, error) {
+	if !interactive {
+		return nil, ErrNoInput
+	}
+
+	selected := make([]bool, len(items))
+	for i := range selected {
+		selected[i] = preselected
+	}
+
+	templates := std.selectTemplates(
+		label,
+		"{{ if .Done }}{{ .Label | green | bold }}{{ else }}{{ if .Selected }}{{ \"[x]\" | green | bold }}{{ else }}[ ]{{ end }} {{ .Label | cyan | bold }}{{ end }}",
+		"{{ if .Done }}{{ .Label | green }}{{ else }}{{ if .Selected }}{{ \"[x]\" | green }}{{ else }}[ ]{{ end }} {{ .Label }}{{ end }}",
+		"",
+		"Enter toggles an item, select Done to confirm",
+	)
+
+	cursorPos := 1
+	for {
+		count := 0
+		for _, isSelected := range selected {
+			if isSelected {
+				count++
+			}
+		}
+
+		rows := make([]multiSelectItem, 0, len(items)+1)
+		rows = append(rows, multiSelectItem{Label: fmt.Sprintf("Done (%d selected)", count), Done: true})
+		for i, item := range items {
+			rows = append(rows, multiSelectItem{Label: item, Selected: selected[i]})
+		}
+		if cursorPos >= len(rows) {
+			cursorPos = 0
+		}
+
+		prompt := promptui.Select{
+			Label:        label,
+			Items:        rows,
+			Templates:    templates,
+			Size:         selectorSize,
+			CursorPos:    cursorPos,
+			HideSelected: true,
 		}
+
+		index, _, err := prompt.Run()
+		if err != nil {
+			return nil, canceled(err)
+		}
+
+		if index == 0 {
+			var result []int
+			for i, isSelected := range selected {
+				if isSelected {
+					result = append(result, i)
+				}
+			}
+			return result, nil
+		}
+
+		selected[index-1] = !selected[index-1]
+		cursorPos = index
 	}
 }
 
 // ConfirmAction shows a yes/no confirmation prompt for potentially destructive actions.
 // It returns true if the user confirms, false if the user cancels.
+// It returns ErrNoInput if interactive prompts are disabled.
 func ConfirmAction(message string) (bool, error) {
+	if !interactive {
+		return false, ErrNoInput
+	}
+
 	prompt := promptui.Prompt{
 		Label:     message,
 		IsConfirm: true,
+		Templates: std.promptTemplates(),
 	}
 
 	_, err := prompt.Run()
@@ -285,8 +430,29 @@ func ConfirmAction(message string) (bool, error) {
 		if err == promptui.ErrAbort {
 			return false, nil
 		}
-		return false, err
+		return false, canceled(err)
 	}
 
 	return true, nil
 }
+
+// ConfirmTyped asks the user to type expected to confirm an action
+// It returns true only if the input matches exactly, and ErrNoInput if
+// interactive prompts are disabled.
+func ConfirmTyped(message, expected string) (bool, error) {
+	if !interactive {
+		return false, ErrNoInput
+	}
+
+	prompt := promptui.Prompt{
+		Label:     message,
+		Templates: std.promptTemplates(),
+	}
+
+	input, err := prompt.Run()
+	if err != nil {
+		return false, canceled(err)
+	}
+
+	return strings.TrimSpace(input) == expected, nil
+}
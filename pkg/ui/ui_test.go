@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/manifoldco/promptui"
 )
 
 func TestSortContexts(t *testing.T) {
@@ -204,3 +207,84 @@ func TestSortNamespacesDoesNotModifyOriginal(t *testing.T) {
 		}
 	}
 }
+
+func TestCanceled(t *testing.T) {
+	other := errors.New("terminal error")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "Ctrl+C", err: promptui.ErrInterrupt, want: ErrSelectionCanceled},
+		{name: "Ctrl+D", err: promptui.ErrEOF, want: ErrSelectionCanceled},
+		{name: "Other errors are kept", err: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canceled(tt.err); !errors.Is(got, tt.want) {
+				t.Errorf("canceled(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptsFailWithoutInput(t *testing.T) {
+	SetInteractive(false)
+	defer SetInteractive(true)
+
+	if _, err := RunSelector(CreateContextSelector([]string{"dev"}, "dev")); !errors.Is(err, ErrNoInput) {
+		t.Errorf("RunSelector() error = %v, want %v", err, ErrNoInput)
+	}
+	if _, err := MultiSelect("Select", []string{"dev"}, false); !errors.Is(err, ErrNoInput) {
+		t.Errorf("MultiSelect() error = %v, want %v", err, ErrNoInput)
+	}
+	if _, err := ConfirmAction("Delete?"); !errors.Is(err, ErrNoInput) {
+		t.Errorf("ConfirmAction() error = %v, want %v", err, ErrNoInput)
+	}
+}
+
+func TestSetASCII(t *testing.T) {
+	SetASCII(true)
+	defer SetASCII(false)
+
+	glyphs := GetGlyphs()
+	for _, glyph := range []string{glyphs.Success, glyphs.Error, glyphs.Warning, glyphs.Info, glyphs.Arrow, glyphs.Rule} {
+		for _, r := range glyph {
+			if r > 127 {
+				t.Errorf("glyph %q is not ASCII", glyph)
+			}
+		}
+	}
+
+	SetASCII(false)
+	if GetGlyphs() != unicodeGlyphs {
+		t.Errorf("SetASCII(false) did not restore the unicode glyphs")
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ColorMode
+		wantErr bool
+	}{
+		{value: "", want: ColorAuto},
+		{value: "auto", want: ColorAuto},
+		{value: "ALWAYS", want: ColorAlways},
+		{value: "never", want: ColorNever},
+		{value: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseColorMode(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseColorMode(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseColorMode(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}